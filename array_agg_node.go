@@ -0,0 +1,225 @@
+package rel
+
+type ArrayAggNode FunctionNode
+
+func ArrayAgg(visitable Visitable) *ArrayAggNode {
+	return &ArrayAggNode{Expressions: []Visitable{visitable}}
+}
+
+func (node *ArrayAggNode) Distinct() *ArrayAggNode {
+	node.DistinctValues = true
+	return node
+}
+
+func (node *ArrayAggNode) Filter(visitable Visitable) *ArrayAggNode {
+	node.FilterExpr = visitable
+	return node
+}
+
+func (node *ArrayAggNode) Desc() *DescendingNode {
+	return orderingDesc(node)
+}
+
+func (node *ArrayAggNode) Asc() *AscendingNode {
+	return orderingAsc(node)
+}
+
+func (node *ArrayAggNode) As(literal SqlLiteralNode) *AsNode {
+	return aliasPredicationAs(node, literal)
+}
+
+func (node *ArrayAggNode) Over(visitable Visitable) *OverNode {
+	return windowPredicationOver(node, visitable)
+}
+
+func (node *ArrayAggNode) Eq(visitable Visitable) *EqualityNode {
+	return predicationEq(node, visitable)
+}
+
+func (node *ArrayAggNode) EqAny(visitables ...Visitable) *GroupingNode {
+	return predicationEqAny(node, visitables...)
+}
+
+func (node *ArrayAggNode) EqAll(visitables ...Visitable) *GroupingNode {
+	return predicationEqAll(node, visitables...)
+}
+
+func (node *ArrayAggNode) Lt(visitable Visitable) *LessThanNode {
+	return predicationLt(node, visitable)
+}
+
+func (node *ArrayAggNode) LtAny(visitables ...Visitable) *GroupingNode {
+	return predicationLtAny(node, visitables...)
+}
+
+func (node *ArrayAggNode) LtAll(visitables ...Visitable) *GroupingNode {
+	return predicationLtAll(node, visitables...)
+}
+
+func (node *ArrayAggNode) LtEq(visitable Visitable) *LessThanOrEqualNode {
+	return predicationLtEq(node, visitable)
+}
+
+func (node *ArrayAggNode) LtEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationLtEqAny(node, visitables...)
+}
+
+func (node *ArrayAggNode) LtEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationLtEqAll(node, visitables...)
+}
+
+func (node *ArrayAggNode) Gt(visitable Visitable) *GreaterThanNode {
+	return predicationGt(node, visitable)
+}
+
+func (node *ArrayAggNode) GtAny(visitables ...Visitable) *GroupingNode {
+	return predicationGtAny(node, visitables...)
+}
+
+func (node *ArrayAggNode) GtAll(visitables ...Visitable) *GroupingNode {
+	return predicationGtAll(node, visitables...)
+}
+
+func (node *ArrayAggNode) GtEq(visitable Visitable) *GreaterThanOrEqualNode {
+	return predicationGtEq(node, visitable)
+}
+
+func (node *ArrayAggNode) GtEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationGtEqAny(node, visitables...)
+}
+
+func (node *ArrayAggNode) GtEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationGtEqAll(node, visitables...)
+}
+
+func (node *ArrayAggNode) Count() *CountNode {
+	return predicationCount(node)
+}
+
+func (node *ArrayAggNode) Extract(literal SqlLiteralNode) *ExtractNode {
+	return predicationExtract(node, literal)
+}
+
+func (node *ArrayAggNode) In(visitables []Visitable) Visitable {
+	return predicationIn(node, visitables)
+}
+
+func (node *ArrayAggNode) InAny(visitableslices ...[]Visitable) Visitable {
+	return predicationInAny(node, visitableslices...)
+}
+
+func (node *ArrayAggNode) InAll(visitableslices ...[]Visitable) Visitable {
+	return predicationInAll(node, visitableslices...)
+}
+
+func (node *ArrayAggNode) NotIn(visitables []Visitable) Visitable {
+	return predicationNotIn(node, visitables)
+}
+
+func (node *ArrayAggNode) NotInAny(visitableslices ...[]Visitable) Visitable {
+	return predicationNotInAny(node, visitableslices...)
+}
+
+func (node *ArrayAggNode) NotInAll(visitableslices ...[]Visitable) Visitable {
+	return predicationNotInAll(node, visitableslices...)
+}
+
+func (node *ArrayAggNode) InQuery(mgr *SelectManager) *InNode {
+	return predicationInQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) NotInQuery(mgr *SelectManager) *NotInNode {
+	return predicationNotInQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) EqAnyQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAnyQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) EqAllQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAllQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) NotEqAnyQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAnyQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) NotEqAllQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAllQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) LtAnyQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAnyQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) LtAllQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAllQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) LtEqAnyQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAnyQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) LtEqAllQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAllQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) GtAnyQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAnyQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) GtAllQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAllQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) GtEqAnyQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAnyQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) GtEqAllQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAllQuery(node, mgr)
+}
+
+func (node *ArrayAggNode) NotEq(visitable Visitable) *NotEqualNode {
+	return predicationNotEq(node, visitable)
+}
+
+func (node *ArrayAggNode) NotEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationNotEqAny(node, visitables...)
+}
+
+func (node *ArrayAggNode) NotEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationNotEqAll(node, visitables...)
+}
+
+func (node *ArrayAggNode) IsDistinctFrom(visitable Visitable) *IsDistinctFromNode {
+	return predicationIsDistinctFrom(node, visitable)
+}
+
+func (node *ArrayAggNode) IsNotDistinctFrom(visitable Visitable) *IsNotDistinctFromNode {
+	return predicationIsNotDistinctFrom(node, visitable)
+}
+
+func (node *ArrayAggNode) DoesNotMatch(literal SqlLiteralNode) *DoesNotMatchNode {
+	return predicationDoesNotMatch(node, literal)
+}
+
+func (node *ArrayAggNode) DoesNotMatchAny(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationDoesNotMatchAny(node, literals...)
+}
+
+func (node *ArrayAggNode) DoesNotMatchAll(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationDoesNotMatchAll(node, literals...)
+}
+
+func (node *ArrayAggNode) Matches(literal SqlLiteralNode) *MatchesNode {
+	return predicationMatches(node, literal)
+}
+
+func (node *ArrayAggNode) MatchesAny(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationMatchesAny(node, literals...)
+}
+
+func (node *ArrayAggNode) MatchesAll(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationMatchesAll(node, literals...)
+}
@@ -15,5 +15,15 @@ func (node *AscendingNode) Direction() string {
 }
 
 func (node *AscendingNode) Reverse() *DescendingNode {
-	return &DescendingNode{Expr: node.Expr}
+	return &DescendingNode{Expr: node.Expr, Nulls: node.Nulls.reverse()}
+}
+
+func (node *AscendingNode) NullsFirst() *AscendingNode {
+	node.Nulls = NullsFirst
+	return node
+}
+
+func (node *AscendingNode) NullsLast() *AscendingNode {
+	node.Nulls = NullsLast
+	return node
 }
@@ -24,4 +24,12 @@ var _ = Describe("AscendingNode", func() {
 		Expect(desc.Expr).To(Equal(Sql("zomg")))
 		Expect(desc.Direction()).To(Equal("DESC"))
 	})
+
+	It("places NULL values and reverses their placement", func() {
+		asc := (&AscendingNode{Expr: Sql("zomg")}).NullsLast()
+		Expect(asc.Nulls).To(Equal(NullsLast))
+		Expect(asc.Reverse().Nulls).To(Equal(NullsFirst))
+		visitor := &PostgreSQLVisitor{Conn: DefaultConnector{}}
+		Expect(visitor.Accept(asc)).To(Equal("zomg ASC NULLS LAST"))
+	})
 })
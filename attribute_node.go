@@ -117,6 +117,62 @@ func (node *AttributeNode) NotInAll(visitableslices ...[]Visitable) Visitable {
 	return predicationNotInAll(node, visitableslices...)
 }
 
+func (node *AttributeNode) InQuery(mgr *SelectManager) *InNode {
+	return predicationInQuery(node, mgr)
+}
+
+func (node *AttributeNode) NotInQuery(mgr *SelectManager) *NotInNode {
+	return predicationNotInQuery(node, mgr)
+}
+
+func (node *AttributeNode) EqAnyQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAnyQuery(node, mgr)
+}
+
+func (node *AttributeNode) EqAllQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAllQuery(node, mgr)
+}
+
+func (node *AttributeNode) NotEqAnyQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAnyQuery(node, mgr)
+}
+
+func (node *AttributeNode) NotEqAllQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAllQuery(node, mgr)
+}
+
+func (node *AttributeNode) LtAnyQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAnyQuery(node, mgr)
+}
+
+func (node *AttributeNode) LtAllQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAllQuery(node, mgr)
+}
+
+func (node *AttributeNode) LtEqAnyQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAnyQuery(node, mgr)
+}
+
+func (node *AttributeNode) LtEqAllQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAllQuery(node, mgr)
+}
+
+func (node *AttributeNode) GtAnyQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAnyQuery(node, mgr)
+}
+
+func (node *AttributeNode) GtAllQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAllQuery(node, mgr)
+}
+
+func (node *AttributeNode) GtEqAnyQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAnyQuery(node, mgr)
+}
+
+func (node *AttributeNode) GtEqAllQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAllQuery(node, mgr)
+}
+
 func (node *AttributeNode) NotEq(visitable Visitable) *NotEqualNode {
 	return predicationNotEq(node, visitable)
 }
@@ -129,6 +185,14 @@ func (node *AttributeNode) NotEqAll(visitables ...Visitable) *GroupingNode {
 	return predicationNotEqAll(node, visitables...)
 }
 
+func (node *AttributeNode) IsDistinctFrom(visitable Visitable) *IsDistinctFromNode {
+	return predicationIsDistinctFrom(node, visitable)
+}
+
+func (node *AttributeNode) IsNotDistinctFrom(visitable Visitable) *IsNotDistinctFromNode {
+	return predicationIsNotDistinctFrom(node, visitable)
+}
+
 func (node *AttributeNode) DoesNotMatch(literal SqlLiteralNode) *DoesNotMatchNode {
 	return predicationDoesNotMatch(node, literal)
 }
@@ -269,4 +269,25 @@ var _ = Describe("AttributeNode", func() {
 		expected := `SELECT "users"."id" FROM "users" WHERE ("users"."id" NOT IN (1, 2) AND "users"."id" NOT IN (3, 4))`
 		Expect(sql).To(Equal(expected))
 	})
+
+	It("can use the IsDistinctFrom predication", func() {
+		mgr.Where(users.Attr("id").IsDistinctFrom(users.Attr("parent_id")))
+		sql := mgr.ToSql()
+		expected := `SELECT "users"."id" FROM "users" WHERE NOT (("users"."id" = "users"."parent_id" AND "users"."id" IS NOT NULL AND "users"."parent_id" IS NOT NULL) OR ("users"."id" IS NULL AND "users"."parent_id" IS NULL))`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("can use the IsNotDistinctFrom predication", func() {
+		mgr.Where(users.Attr("id").IsNotDistinctFrom(users.Attr("parent_id")))
+		sql := mgr.ToSql()
+		expected := `SELECT "users"."id" FROM "users" WHERE (("users"."id" = "users"."parent_id" AND "users"."id" IS NOT NULL AND "users"."parent_id" IS NOT NULL) OR ("users"."id" IS NULL AND "users"."parent_id" IS NULL))`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("can use the IsDistinctFrom predication allowing for nil input", func() {
+		mgr.Where(users.Attr("id").IsDistinctFrom(nil))
+		sql := mgr.ToSql()
+		expected := `SELECT "users"."id" FROM "users" WHERE "users"."id" IS NOT NULL`
+		Expect(sql).To(Equal(expected))
+	})
 })
@@ -0,0 +1,225 @@
+package rel
+
+type AvgNode FunctionNode
+
+func Avg(visitable Visitable) *AvgNode {
+	return &AvgNode{Expressions: []Visitable{visitable}}
+}
+
+func (node *AvgNode) Distinct() *AvgNode {
+	node.DistinctValues = true
+	return node
+}
+
+func (node *AvgNode) Filter(visitable Visitable) *AvgNode {
+	node.FilterExpr = visitable
+	return node
+}
+
+func (node *AvgNode) Desc() *DescendingNode {
+	return orderingDesc(node)
+}
+
+func (node *AvgNode) Asc() *AscendingNode {
+	return orderingAsc(node)
+}
+
+func (node *AvgNode) As(literal SqlLiteralNode) *AsNode {
+	return aliasPredicationAs(node, literal)
+}
+
+func (node *AvgNode) Over(visitable Visitable) *OverNode {
+	return windowPredicationOver(node, visitable)
+}
+
+func (node *AvgNode) Eq(visitable Visitable) *EqualityNode {
+	return predicationEq(node, visitable)
+}
+
+func (node *AvgNode) EqAny(visitables ...Visitable) *GroupingNode {
+	return predicationEqAny(node, visitables...)
+}
+
+func (node *AvgNode) EqAll(visitables ...Visitable) *GroupingNode {
+	return predicationEqAll(node, visitables...)
+}
+
+func (node *AvgNode) Lt(visitable Visitable) *LessThanNode {
+	return predicationLt(node, visitable)
+}
+
+func (node *AvgNode) LtAny(visitables ...Visitable) *GroupingNode {
+	return predicationLtAny(node, visitables...)
+}
+
+func (node *AvgNode) LtAll(visitables ...Visitable) *GroupingNode {
+	return predicationLtAll(node, visitables...)
+}
+
+func (node *AvgNode) LtEq(visitable Visitable) *LessThanOrEqualNode {
+	return predicationLtEq(node, visitable)
+}
+
+func (node *AvgNode) LtEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationLtEqAny(node, visitables...)
+}
+
+func (node *AvgNode) LtEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationLtEqAll(node, visitables...)
+}
+
+func (node *AvgNode) Gt(visitable Visitable) *GreaterThanNode {
+	return predicationGt(node, visitable)
+}
+
+func (node *AvgNode) GtAny(visitables ...Visitable) *GroupingNode {
+	return predicationGtAny(node, visitables...)
+}
+
+func (node *AvgNode) GtAll(visitables ...Visitable) *GroupingNode {
+	return predicationGtAll(node, visitables...)
+}
+
+func (node *AvgNode) GtEq(visitable Visitable) *GreaterThanOrEqualNode {
+	return predicationGtEq(node, visitable)
+}
+
+func (node *AvgNode) GtEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationGtEqAny(node, visitables...)
+}
+
+func (node *AvgNode) GtEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationGtEqAll(node, visitables...)
+}
+
+func (node *AvgNode) Count() *CountNode {
+	return predicationCount(node)
+}
+
+func (node *AvgNode) Extract(literal SqlLiteralNode) *ExtractNode {
+	return predicationExtract(node, literal)
+}
+
+func (node *AvgNode) In(visitables []Visitable) Visitable {
+	return predicationIn(node, visitables)
+}
+
+func (node *AvgNode) InAny(visitableslices ...[]Visitable) Visitable {
+	return predicationInAny(node, visitableslices...)
+}
+
+func (node *AvgNode) InAll(visitableslices ...[]Visitable) Visitable {
+	return predicationInAll(node, visitableslices...)
+}
+
+func (node *AvgNode) NotIn(visitables []Visitable) Visitable {
+	return predicationNotIn(node, visitables)
+}
+
+func (node *AvgNode) NotInAny(visitableslices ...[]Visitable) Visitable {
+	return predicationNotInAny(node, visitableslices...)
+}
+
+func (node *AvgNode) NotInAll(visitableslices ...[]Visitable) Visitable {
+	return predicationNotInAll(node, visitableslices...)
+}
+
+func (node *AvgNode) InQuery(mgr *SelectManager) *InNode {
+	return predicationInQuery(node, mgr)
+}
+
+func (node *AvgNode) NotInQuery(mgr *SelectManager) *NotInNode {
+	return predicationNotInQuery(node, mgr)
+}
+
+func (node *AvgNode) EqAnyQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAnyQuery(node, mgr)
+}
+
+func (node *AvgNode) EqAllQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAllQuery(node, mgr)
+}
+
+func (node *AvgNode) NotEqAnyQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAnyQuery(node, mgr)
+}
+
+func (node *AvgNode) NotEqAllQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAllQuery(node, mgr)
+}
+
+func (node *AvgNode) LtAnyQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAnyQuery(node, mgr)
+}
+
+func (node *AvgNode) LtAllQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAllQuery(node, mgr)
+}
+
+func (node *AvgNode) LtEqAnyQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAnyQuery(node, mgr)
+}
+
+func (node *AvgNode) LtEqAllQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAllQuery(node, mgr)
+}
+
+func (node *AvgNode) GtAnyQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAnyQuery(node, mgr)
+}
+
+func (node *AvgNode) GtAllQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAllQuery(node, mgr)
+}
+
+func (node *AvgNode) GtEqAnyQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAnyQuery(node, mgr)
+}
+
+func (node *AvgNode) GtEqAllQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAllQuery(node, mgr)
+}
+
+func (node *AvgNode) NotEq(visitable Visitable) *NotEqualNode {
+	return predicationNotEq(node, visitable)
+}
+
+func (node *AvgNode) NotEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationNotEqAny(node, visitables...)
+}
+
+func (node *AvgNode) NotEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationNotEqAll(node, visitables...)
+}
+
+func (node *AvgNode) IsDistinctFrom(visitable Visitable) *IsDistinctFromNode {
+	return predicationIsDistinctFrom(node, visitable)
+}
+
+func (node *AvgNode) IsNotDistinctFrom(visitable Visitable) *IsNotDistinctFromNode {
+	return predicationIsNotDistinctFrom(node, visitable)
+}
+
+func (node *AvgNode) DoesNotMatch(literal SqlLiteralNode) *DoesNotMatchNode {
+	return predicationDoesNotMatch(node, literal)
+}
+
+func (node *AvgNode) DoesNotMatchAny(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationDoesNotMatchAny(node, literals...)
+}
+
+func (node *AvgNode) DoesNotMatchAll(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationDoesNotMatchAll(node, literals...)
+}
+
+func (node *AvgNode) Matches(literal SqlLiteralNode) *MatchesNode {
+	return predicationMatches(node, literal)
+}
+
+func (node *AvgNode) MatchesAny(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationMatchesAny(node, literals...)
+}
+
+func (node *AvgNode) MatchesAll(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationMatchesAll(node, literals...)
+}
@@ -18,6 +18,8 @@ type AssignmentNode BinaryNode
 type DoesNotMatchNode BinaryNode
 type GreaterThanNode BinaryNode
 type GreaterThanOrEqualNode BinaryNode
+type IsDistinctFromNode BinaryNode
+type IsNotDistinctFromNode BinaryNode
 type JoinNode BinaryNode
 type LessThanNode BinaryNode
 type LessThanOrEqualNode BinaryNode
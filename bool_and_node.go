@@ -0,0 +1,225 @@
+package rel
+
+type BoolAndNode FunctionNode
+
+func BoolAnd(visitable Visitable) *BoolAndNode {
+	return &BoolAndNode{Expressions: []Visitable{visitable}}
+}
+
+func (node *BoolAndNode) Distinct() *BoolAndNode {
+	node.DistinctValues = true
+	return node
+}
+
+func (node *BoolAndNode) Filter(visitable Visitable) *BoolAndNode {
+	node.FilterExpr = visitable
+	return node
+}
+
+func (node *BoolAndNode) Desc() *DescendingNode {
+	return orderingDesc(node)
+}
+
+func (node *BoolAndNode) Asc() *AscendingNode {
+	return orderingAsc(node)
+}
+
+func (node *BoolAndNode) As(literal SqlLiteralNode) *AsNode {
+	return aliasPredicationAs(node, literal)
+}
+
+func (node *BoolAndNode) Over(visitable Visitable) *OverNode {
+	return windowPredicationOver(node, visitable)
+}
+
+func (node *BoolAndNode) Eq(visitable Visitable) *EqualityNode {
+	return predicationEq(node, visitable)
+}
+
+func (node *BoolAndNode) EqAny(visitables ...Visitable) *GroupingNode {
+	return predicationEqAny(node, visitables...)
+}
+
+func (node *BoolAndNode) EqAll(visitables ...Visitable) *GroupingNode {
+	return predicationEqAll(node, visitables...)
+}
+
+func (node *BoolAndNode) Lt(visitable Visitable) *LessThanNode {
+	return predicationLt(node, visitable)
+}
+
+func (node *BoolAndNode) LtAny(visitables ...Visitable) *GroupingNode {
+	return predicationLtAny(node, visitables...)
+}
+
+func (node *BoolAndNode) LtAll(visitables ...Visitable) *GroupingNode {
+	return predicationLtAll(node, visitables...)
+}
+
+func (node *BoolAndNode) LtEq(visitable Visitable) *LessThanOrEqualNode {
+	return predicationLtEq(node, visitable)
+}
+
+func (node *BoolAndNode) LtEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationLtEqAny(node, visitables...)
+}
+
+func (node *BoolAndNode) LtEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationLtEqAll(node, visitables...)
+}
+
+func (node *BoolAndNode) Gt(visitable Visitable) *GreaterThanNode {
+	return predicationGt(node, visitable)
+}
+
+func (node *BoolAndNode) GtAny(visitables ...Visitable) *GroupingNode {
+	return predicationGtAny(node, visitables...)
+}
+
+func (node *BoolAndNode) GtAll(visitables ...Visitable) *GroupingNode {
+	return predicationGtAll(node, visitables...)
+}
+
+func (node *BoolAndNode) GtEq(visitable Visitable) *GreaterThanOrEqualNode {
+	return predicationGtEq(node, visitable)
+}
+
+func (node *BoolAndNode) GtEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationGtEqAny(node, visitables...)
+}
+
+func (node *BoolAndNode) GtEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationGtEqAll(node, visitables...)
+}
+
+func (node *BoolAndNode) Count() *CountNode {
+	return predicationCount(node)
+}
+
+func (node *BoolAndNode) Extract(literal SqlLiteralNode) *ExtractNode {
+	return predicationExtract(node, literal)
+}
+
+func (node *BoolAndNode) In(visitables []Visitable) Visitable {
+	return predicationIn(node, visitables)
+}
+
+func (node *BoolAndNode) InAny(visitableslices ...[]Visitable) Visitable {
+	return predicationInAny(node, visitableslices...)
+}
+
+func (node *BoolAndNode) InAll(visitableslices ...[]Visitable) Visitable {
+	return predicationInAll(node, visitableslices...)
+}
+
+func (node *BoolAndNode) NotIn(visitables []Visitable) Visitable {
+	return predicationNotIn(node, visitables)
+}
+
+func (node *BoolAndNode) NotInAny(visitableslices ...[]Visitable) Visitable {
+	return predicationNotInAny(node, visitableslices...)
+}
+
+func (node *BoolAndNode) NotInAll(visitableslices ...[]Visitable) Visitable {
+	return predicationNotInAll(node, visitableslices...)
+}
+
+func (node *BoolAndNode) InQuery(mgr *SelectManager) *InNode {
+	return predicationInQuery(node, mgr)
+}
+
+func (node *BoolAndNode) NotInQuery(mgr *SelectManager) *NotInNode {
+	return predicationNotInQuery(node, mgr)
+}
+
+func (node *BoolAndNode) EqAnyQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAnyQuery(node, mgr)
+}
+
+func (node *BoolAndNode) EqAllQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAllQuery(node, mgr)
+}
+
+func (node *BoolAndNode) NotEqAnyQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAnyQuery(node, mgr)
+}
+
+func (node *BoolAndNode) NotEqAllQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAllQuery(node, mgr)
+}
+
+func (node *BoolAndNode) LtAnyQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAnyQuery(node, mgr)
+}
+
+func (node *BoolAndNode) LtAllQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAllQuery(node, mgr)
+}
+
+func (node *BoolAndNode) LtEqAnyQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAnyQuery(node, mgr)
+}
+
+func (node *BoolAndNode) LtEqAllQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAllQuery(node, mgr)
+}
+
+func (node *BoolAndNode) GtAnyQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAnyQuery(node, mgr)
+}
+
+func (node *BoolAndNode) GtAllQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAllQuery(node, mgr)
+}
+
+func (node *BoolAndNode) GtEqAnyQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAnyQuery(node, mgr)
+}
+
+func (node *BoolAndNode) GtEqAllQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAllQuery(node, mgr)
+}
+
+func (node *BoolAndNode) NotEq(visitable Visitable) *NotEqualNode {
+	return predicationNotEq(node, visitable)
+}
+
+func (node *BoolAndNode) NotEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationNotEqAny(node, visitables...)
+}
+
+func (node *BoolAndNode) NotEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationNotEqAll(node, visitables...)
+}
+
+func (node *BoolAndNode) IsDistinctFrom(visitable Visitable) *IsDistinctFromNode {
+	return predicationIsDistinctFrom(node, visitable)
+}
+
+func (node *BoolAndNode) IsNotDistinctFrom(visitable Visitable) *IsNotDistinctFromNode {
+	return predicationIsNotDistinctFrom(node, visitable)
+}
+
+func (node *BoolAndNode) DoesNotMatch(literal SqlLiteralNode) *DoesNotMatchNode {
+	return predicationDoesNotMatch(node, literal)
+}
+
+func (node *BoolAndNode) DoesNotMatchAny(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationDoesNotMatchAny(node, literals...)
+}
+
+func (node *BoolAndNode) DoesNotMatchAll(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationDoesNotMatchAll(node, literals...)
+}
+
+func (node *BoolAndNode) Matches(literal SqlLiteralNode) *MatchesNode {
+	return predicationMatches(node, literal)
+}
+
+func (node *BoolAndNode) MatchesAny(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationMatchesAny(node, literals...)
+}
+
+func (node *BoolAndNode) MatchesAll(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationMatchesAll(node, literals...)
+}
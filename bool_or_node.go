@@ -0,0 +1,225 @@
+package rel
+
+type BoolOrNode FunctionNode
+
+func BoolOr(visitable Visitable) *BoolOrNode {
+	return &BoolOrNode{Expressions: []Visitable{visitable}}
+}
+
+func (node *BoolOrNode) Distinct() *BoolOrNode {
+	node.DistinctValues = true
+	return node
+}
+
+func (node *BoolOrNode) Filter(visitable Visitable) *BoolOrNode {
+	node.FilterExpr = visitable
+	return node
+}
+
+func (node *BoolOrNode) Desc() *DescendingNode {
+	return orderingDesc(node)
+}
+
+func (node *BoolOrNode) Asc() *AscendingNode {
+	return orderingAsc(node)
+}
+
+func (node *BoolOrNode) As(literal SqlLiteralNode) *AsNode {
+	return aliasPredicationAs(node, literal)
+}
+
+func (node *BoolOrNode) Over(visitable Visitable) *OverNode {
+	return windowPredicationOver(node, visitable)
+}
+
+func (node *BoolOrNode) Eq(visitable Visitable) *EqualityNode {
+	return predicationEq(node, visitable)
+}
+
+func (node *BoolOrNode) EqAny(visitables ...Visitable) *GroupingNode {
+	return predicationEqAny(node, visitables...)
+}
+
+func (node *BoolOrNode) EqAll(visitables ...Visitable) *GroupingNode {
+	return predicationEqAll(node, visitables...)
+}
+
+func (node *BoolOrNode) Lt(visitable Visitable) *LessThanNode {
+	return predicationLt(node, visitable)
+}
+
+func (node *BoolOrNode) LtAny(visitables ...Visitable) *GroupingNode {
+	return predicationLtAny(node, visitables...)
+}
+
+func (node *BoolOrNode) LtAll(visitables ...Visitable) *GroupingNode {
+	return predicationLtAll(node, visitables...)
+}
+
+func (node *BoolOrNode) LtEq(visitable Visitable) *LessThanOrEqualNode {
+	return predicationLtEq(node, visitable)
+}
+
+func (node *BoolOrNode) LtEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationLtEqAny(node, visitables...)
+}
+
+func (node *BoolOrNode) LtEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationLtEqAll(node, visitables...)
+}
+
+func (node *BoolOrNode) Gt(visitable Visitable) *GreaterThanNode {
+	return predicationGt(node, visitable)
+}
+
+func (node *BoolOrNode) GtAny(visitables ...Visitable) *GroupingNode {
+	return predicationGtAny(node, visitables...)
+}
+
+func (node *BoolOrNode) GtAll(visitables ...Visitable) *GroupingNode {
+	return predicationGtAll(node, visitables...)
+}
+
+func (node *BoolOrNode) GtEq(visitable Visitable) *GreaterThanOrEqualNode {
+	return predicationGtEq(node, visitable)
+}
+
+func (node *BoolOrNode) GtEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationGtEqAny(node, visitables...)
+}
+
+func (node *BoolOrNode) GtEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationGtEqAll(node, visitables...)
+}
+
+func (node *BoolOrNode) Count() *CountNode {
+	return predicationCount(node)
+}
+
+func (node *BoolOrNode) Extract(literal SqlLiteralNode) *ExtractNode {
+	return predicationExtract(node, literal)
+}
+
+func (node *BoolOrNode) In(visitables []Visitable) Visitable {
+	return predicationIn(node, visitables)
+}
+
+func (node *BoolOrNode) InAny(visitableslices ...[]Visitable) Visitable {
+	return predicationInAny(node, visitableslices...)
+}
+
+func (node *BoolOrNode) InAll(visitableslices ...[]Visitable) Visitable {
+	return predicationInAll(node, visitableslices...)
+}
+
+func (node *BoolOrNode) NotIn(visitables []Visitable) Visitable {
+	return predicationNotIn(node, visitables)
+}
+
+func (node *BoolOrNode) NotInAny(visitableslices ...[]Visitable) Visitable {
+	return predicationNotInAny(node, visitableslices...)
+}
+
+func (node *BoolOrNode) NotInAll(visitableslices ...[]Visitable) Visitable {
+	return predicationNotInAll(node, visitableslices...)
+}
+
+func (node *BoolOrNode) InQuery(mgr *SelectManager) *InNode {
+	return predicationInQuery(node, mgr)
+}
+
+func (node *BoolOrNode) NotInQuery(mgr *SelectManager) *NotInNode {
+	return predicationNotInQuery(node, mgr)
+}
+
+func (node *BoolOrNode) EqAnyQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAnyQuery(node, mgr)
+}
+
+func (node *BoolOrNode) EqAllQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAllQuery(node, mgr)
+}
+
+func (node *BoolOrNode) NotEqAnyQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAnyQuery(node, mgr)
+}
+
+func (node *BoolOrNode) NotEqAllQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAllQuery(node, mgr)
+}
+
+func (node *BoolOrNode) LtAnyQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAnyQuery(node, mgr)
+}
+
+func (node *BoolOrNode) LtAllQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAllQuery(node, mgr)
+}
+
+func (node *BoolOrNode) LtEqAnyQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAnyQuery(node, mgr)
+}
+
+func (node *BoolOrNode) LtEqAllQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAllQuery(node, mgr)
+}
+
+func (node *BoolOrNode) GtAnyQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAnyQuery(node, mgr)
+}
+
+func (node *BoolOrNode) GtAllQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAllQuery(node, mgr)
+}
+
+func (node *BoolOrNode) GtEqAnyQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAnyQuery(node, mgr)
+}
+
+func (node *BoolOrNode) GtEqAllQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAllQuery(node, mgr)
+}
+
+func (node *BoolOrNode) NotEq(visitable Visitable) *NotEqualNode {
+	return predicationNotEq(node, visitable)
+}
+
+func (node *BoolOrNode) NotEqAny(visitables ...Visitable) *GroupingNode {
+	return predicationNotEqAny(node, visitables...)
+}
+
+func (node *BoolOrNode) NotEqAll(visitables ...Visitable) *GroupingNode {
+	return predicationNotEqAll(node, visitables...)
+}
+
+func (node *BoolOrNode) IsDistinctFrom(visitable Visitable) *IsDistinctFromNode {
+	return predicationIsDistinctFrom(node, visitable)
+}
+
+func (node *BoolOrNode) IsNotDistinctFrom(visitable Visitable) *IsNotDistinctFromNode {
+	return predicationIsNotDistinctFrom(node, visitable)
+}
+
+func (node *BoolOrNode) DoesNotMatch(literal SqlLiteralNode) *DoesNotMatchNode {
+	return predicationDoesNotMatch(node, literal)
+}
+
+func (node *BoolOrNode) DoesNotMatchAny(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationDoesNotMatchAny(node, literals...)
+}
+
+func (node *BoolOrNode) DoesNotMatchAll(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationDoesNotMatchAll(node, literals...)
+}
+
+func (node *BoolOrNode) Matches(literal SqlLiteralNode) *MatchesNode {
+	return predicationMatches(node, literal)
+}
+
+func (node *BoolOrNode) MatchesAny(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationMatchesAny(node, literals...)
+}
+
+func (node *BoolOrNode) MatchesAll(literals ...SqlLiteralNode) *GroupingNode {
+	return predicationMatchesAll(node, literals...)
+}
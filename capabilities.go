@@ -0,0 +1,332 @@
+package rel
+
+import (
+	"fmt"
+	"strings"
+)
+
+type PlaceholderStyle int
+
+const (
+	// ? for every parameter
+	QuestionPlaceholders PlaceholderStyle = iota
+	// $1, $2, ... numbered parameters
+	DollarPlaceholders
+)
+
+type UpsertStyle int
+
+const (
+	NoUpsert UpsertStyle = iota
+	// INSERT ... ON CONFLICT DO UPDATE
+	OnConflictUpsert
+	// INSERT ... ON DUPLICATE KEY UPDATE
+	OnDuplicateKeyUpsert
+)
+
+// Capabilities describes the features a dialect supports natively, the
+// visitor emulates some missing features unless emulation is disabled
+type Capabilities struct {
+	Returning              bool
+	FullJoin               bool
+	DistinctOn             bool
+	WindowFunctions        bool
+	CommonTableExpressions bool
+	IntersectExcept        bool
+	Top                    bool
+	Rollup                 bool
+	// ROLLUP is written as WITH ROLLUP and must be the only grouping element
+	RollupAsModifier bool
+	Cube             bool
+	GroupingSets     bool
+	// NULLS FIRST and NULLS LAST
+	NullsOrdering bool
+	// OFFSET without LIMIT
+	OffsetWithoutLimit bool
+	// column names after an alias, as in AS v(a, b)
+	DerivedColumnLists bool
+	// FILTER (WHERE ...) on every aggregate, without it the condition is
+	// moved into CASE for the aggregates which ignore NULL values
+	AggregateFilter bool
+	// a separator other than a comma for a distinct string aggregate
+	DistinctSeparator bool
+	// comparisons with ANY and ALL other than = ANY and != ALL
+	QuantifiedComparisons bool
+	Upsert                UpsertStyle
+	LockStrengths         []LockStrength
+	LockWaits             []LockWait
+	Placeholder           PlaceholderStyle
+	// :name, @name and $name parameters
+	NamedPlaceholders bool
+}
+
+var (
+	PostgreSQLCapabilities = Capabilities{
+		AggregateFilter:        true,
+		DistinctSeparator:      true,
+		DerivedColumnLists:     true,
+		Returning:              true,
+		FullJoin:               true,
+		DistinctOn:             true,
+		NullsOrdering:          true,
+		OffsetWithoutLimit:     true,
+		WindowFunctions:        true,
+		CommonTableExpressions: true,
+		IntersectExcept:        true,
+		Rollup:                 true,
+		Cube:                   true,
+		GroupingSets:           true,
+		QuantifiedComparisons:  true,
+		Upsert:                 OnConflictUpsert,
+		LockStrengths:          []LockStrength{LockUpdate, LockNoKeyUpdate, LockShare, LockKeyShare},
+		LockWaits:              []LockWait{LockNoWait, LockSkipLocked},
+		Placeholder:            DollarPlaceholders,
+	}
+
+	// MySQL 8.0 before 8.0.31, which added INTERSECT and EXCEPT
+	MysqlCapabilities = Capabilities{
+		DistinctSeparator:      true,
+		DerivedColumnLists:     true,
+		WindowFunctions:        true,
+		CommonTableExpressions: true,
+		Rollup:                 true,
+		RollupAsModifier:       true,
+		QuantifiedComparisons:  true,
+		Upsert:                 OnDuplicateKeyUpsert,
+		LockStrengths:          []LockStrength{LockUpdate, LockShare},
+		LockWaits:              []LockWait{LockNoWait, LockSkipLocked},
+		Placeholder:            QuestionPlaceholders,
+	}
+
+	// SQLite 3.39 or later, which added RIGHT and FULL OUTER JOIN
+	SQLiteCapabilities = Capabilities{
+		AggregateFilter:        true,
+		Returning:              true,
+		FullJoin:               true,
+		NullsOrdering:          true,
+		OffsetWithoutLimit:     true,
+		WindowFunctions:        true,
+		CommonTableExpressions: true,
+		IntersectExcept:        true,
+		Upsert:                 OnConflictUpsert,
+		Placeholder:            QuestionPlaceholders,
+		NamedPlaceholders:      true,
+	}
+
+	// the features rendered by the generic ToSqlVisitor
+	ToSqlCapabilities = Capabilities{
+		AggregateFilter:        true,
+		DistinctSeparator:      true,
+		DerivedColumnLists:     true,
+		DistinctOn:             true,
+		NullsOrdering:          true,
+		OffsetWithoutLimit:     true,
+		WindowFunctions:        true,
+		CommonTableExpressions: true,
+		IntersectExcept:        true,
+		Rollup:                 true,
+		Cube:                   true,
+		GroupingSets:           true,
+		QuantifiedComparisons:  true,
+		LockStrengths:          []LockStrength{LockUpdate, LockNoKeyUpdate, LockShare, LockKeyShare},
+		LockWaits:              []LockWait{LockNoWait, LockSkipLocked},
+		Placeholder:            QuestionPlaceholders,
+	}
+)
+
+// CapabilitiesOf returns the capabilities of a dialect
+// accepted by RegisterDatabase
+func CapabilitiesOf(dialect string) (Capabilities, bool) {
+	switch dialect {
+	case "postgresql":
+		return PostgreSQLCapabilities, true
+	case "mysql":
+		return MysqlCapabilities, true
+	case "sqlite":
+		return SQLiteCapabilities, true
+	}
+	return Capabilities{}, false
+}
+
+// Validate reports every node of a manager or node which the dialect
+// cannot run natively, the error is a *LintError listing all of them.
+// These are the nodes rendered as written when emulation is disabled
+func Validate(node Visitable, dialect string) error {
+	capabilities, ok := CapabilitiesOf(dialect)
+	if !ok {
+		return fmt.Errorf("rel: unknown dialect %q", dialect)
+	}
+	return Lint(node, capabilities.Rules()...).Err()
+}
+
+// Rules returns lint rules reporting the features missing from
+// the capabilities as errors
+func (c Capabilities) Rules() []LintRule {
+	return []LintRule{{
+		Name:     "unsupported",
+		Severity: LintSeverityError,
+		Check:    c.checkNode,
+	}}
+}
+
+func (c Capabilities) checkNode(node Visitable, ctx LintContext) string {
+	switch node := node.(type) {
+	case *SelectStatementNode:
+		if !c.OffsetWithoutLimit && node.Offset != nil && node.Limit == nil {
+			return "OFFSET without LIMIT is not supported"
+		}
+	case *TableAliasNode:
+		if !c.DerivedColumnLists && len(node.Columns) > 0 {
+			return "column lists for aliases are not supported"
+		}
+	case *DistinctOnNode:
+		if !c.DistinctOn {
+			return "DISTINCT ON is not supported"
+		}
+	case *AscendingNode:
+		if !c.NullsOrdering && node.Nulls != NullsDefault {
+			return fmt.Sprintf("%s is not supported", node.Nulls)
+		}
+	case *DescendingNode:
+		if !c.NullsOrdering && node.Nulls != NullsDefault {
+			return fmt.Sprintf("%s is not supported", node.Nulls)
+		}
+	case *FullOuterJoinNode:
+		if !c.FullJoin {
+			return "FULL OUTER JOIN is not supported"
+		}
+	case *TopNode:
+		if !c.Top {
+			return "TOP is not supported"
+		}
+	case *OrderingNode:
+		return "OrderingNode cannot be rendered, use Asc or Desc"
+	case *WithNode, *WithRecursiveNode:
+		if !c.CommonTableExpressions {
+			return "common table expressions are not supported"
+		}
+	case *OverNode, *WindowNode, *NamedWindowNode:
+		if !c.WindowFunctions {
+			return "window functions are not supported"
+		}
+	case *IntersectNode:
+		if !c.IntersectExcept {
+			return "INTERSECT is not supported"
+		}
+	case *ExceptNode:
+		if !c.IntersectExcept {
+			return "EXCEPT is not supported"
+		}
+	case *RollupNode:
+		if !c.Rollup {
+			return "ROLLUP is not supported"
+		}
+	case *CubeNode:
+		if !c.Cube {
+			return "CUBE is not supported"
+		}
+	case *GroupingSetsNode:
+		if !c.GroupingSets {
+			return "GROUPING SETS are not supported"
+		}
+	case *SelectCoreNode:
+		if c.RollupAsModifier && node.Groups != nil && len(*node.Groups) > 1 {
+			for _, group := range *node.Groups {
+				if g, ok := group.(*GroupNode); ok {
+					if _, ok := g.Expr.(*RollupNode); ok {
+						return "ROLLUP must be the only grouping element"
+					}
+				}
+			}
+		}
+	case *EqualityNode:
+		return c.checkQuantified(node.Right, true)
+	case *NotEqualNode:
+		if _, ok := node.Right.(*AllNode); ok {
+			return ""
+		}
+		return c.checkQuantified(node.Right, false)
+	case *GreaterThanNode:
+		return c.checkQuantified(node.Right, false)
+	case *GreaterThanOrEqualNode:
+		return c.checkQuantified(node.Right, false)
+	case *LessThanNode:
+		return c.checkQuantified(node.Right, false)
+	case *LessThanOrEqualNode:
+		return c.checkQuantified(node.Right, false)
+	case *LockNode:
+		if _, ok := node.Expr.(*RowLockNode); !ok && len(c.LockStrengths) == 0 {
+			return "locks are not supported"
+		}
+	case *RowLockNode:
+		return c.checkRowLock(node)
+	case *ArrayAggNode:
+		if !c.AggregateFilter && node.FilterExpr != nil {
+			return "FILTER on array aggregates is not supported"
+		}
+	case *JsonAggNode:
+		if !c.AggregateFilter && node.FilterExpr != nil {
+			return "FILTER on JSON aggregates is not supported"
+		}
+	case *StringAggNode:
+		if !c.DistinctSeparator && node.DistinctValues && len(node.Expressions) > 1 && !isCommaSeparator(node.Expressions[1]) {
+			return "separators other than a comma are not supported in distinct string aggregates"
+		}
+	case *BindParamNode:
+		return c.checkPlaceholder(node.Raw)
+	}
+	return ""
+}
+
+// checkQuantified reports ANY and ALL comparisons, = ANY is always
+// supported as it is rewritten to IN when needed
+func (c Capabilities) checkQuantified(right Visitable, equality bool) string {
+	if c.QuantifiedComparisons {
+		return ""
+	}
+	switch right.(type) {
+	case *AnyNode:
+		if !equality {
+			return "comparisons with ANY other than = ANY are not supported"
+		}
+	case *AllNode:
+		return "comparisons with ALL other than != ALL are not supported"
+	}
+	return ""
+}
+
+func (c Capabilities) checkRowLock(node *RowLockNode) string {
+	supported := false
+	for _, strength := range c.LockStrengths {
+		supported = supported || strength == node.Strength
+	}
+	if !supported {
+		return fmt.Sprintf("FOR %s is not supported", node.Strength)
+	}
+	if node.Wait == LockWaitDefault {
+		return ""
+	}
+	for _, wait := range c.LockWaits {
+		if wait == node.Wait {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s is not supported", node.Wait)
+}
+
+func (c Capabilities) checkPlaceholder(raw string) string {
+	if raw == "" || c.NamedPlaceholders {
+		return ""
+	}
+	switch c.Placeholder {
+	case QuestionPlaceholders:
+		if raw != "?" {
+			return fmt.Sprintf("placeholder %s is not supported, use ?", raw)
+		}
+	case DollarPlaceholders:
+		if !strings.HasPrefix(raw, "$") {
+			return fmt.Sprintf("placeholder %s is not supported, use $1", raw)
+		}
+	}
+	return ""
+}
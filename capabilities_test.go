@@ -0,0 +1,70 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Capabilities", func() {
+	users := NewTable("users")
+
+	It("is published by every dialect visitor", func() {
+		Expect((&PostgreSQLVisitor{}).Capabilities().Placeholder).To(Equal(DollarPlaceholders))
+		Expect(MysqlVisitor{}.Capabilities().Upsert).To(Equal(OnDuplicateKeyUpsert))
+		Expect(SQLiteVisitor{}.Capabilities().LockStrengths).To(BeEmpty())
+		Expect((&ToSqlVisitor{}).Capabilities().Returning).To(BeFalse())
+
+		capabilities, ok := CapabilitiesOf("sqlite")
+		Expect(ok).To(BeTrue())
+		Expect(capabilities.Returning).To(BeTrue())
+		_, ok = CapabilitiesOf("oracle")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("validates a supported statement", func() {
+		mgr := users.Select(users.Attr("id")).Where(users.Attr("id").Eq(NewBindParamNode("$1")))
+		mgr.RowLock(LockNoKeyUpdate).Wait = LockSkipLocked
+		mgr.Group(Cube(users.Attr("a"), users.Attr("b")))
+		Expect(Validate(mgr, "postgresql")).To(Succeed())
+	})
+
+	It("reports every unsupported node at once", func() {
+		mgr := users.Select(users.Attr("id")).Where(users.Attr("id").Eq(NewBindParamNode("$1")))
+		mgr.Group(Cube(users.Attr("a"), users.Attr("b")))
+		mgr.RowLock(LockUpdate)
+		err := Validate(mgr, "sqlite")
+		Expect(err).To(HaveOccurred())
+		issues := err.(*LintError).Issues
+		Expect(issues).To(HaveLen(2))
+		Expect(issues[0].Message).To(Equal("CUBE is not supported"))
+		Expect(issues[1].Message).To(Equal("FOR UPDATE is not supported"))
+
+		err = Validate(mgr, "mysql")
+		Expect(err.(*LintError).Issues).To(HaveLen(2))
+		Expect(err.Error()).To(ContainSubstring("placeholder $1 is not supported"))
+	})
+
+	It("reports lock modes, rollups and quantified comparisons", func() {
+		mgr := users.Select(users.Attr("id"))
+		mgr.RowLock(LockKeyShare)
+		Expect(Validate(mgr, "mysql")).To(MatchError(ContainSubstring("FOR KEY SHARE is not supported")))
+
+		grouped := users.Select(users.Attr("id")).Group(users.Attr("a"), Rollup(users.Attr("b")))
+		Expect(Validate(grouped, "mysql")).To(MatchError(ContainSubstring("ROLLUP must be the only grouping element")))
+		Expect(Validate(grouped, "sqlite")).To(MatchError(ContainSubstring("ROLLUP is not supported")))
+		Expect(Validate(grouped, "postgresql")).To(Succeed())
+
+		ids := users.Select(users.Attr("id"))
+		quantified := users.Where(users.Attr("id").GtAnyQuery(ids))
+		Expect(Validate(quantified, "sqlite")).To(HaveOccurred())
+		Expect(Validate(users.Where(users.Attr("id").EqAnyQuery(ids)), "sqlite")).To(Succeed())
+	})
+
+	It("reports set operations missing from older MySQL", func() {
+		mgr := users.Select().Intersect(users.Select(users.Attr("id")).Ast, users.Select(users.Attr("id")).Ast)
+		Expect(Validate(mgr, "mysql")).To(MatchError(ContainSubstring("INTERSECT is not supported")))
+		Expect(Validate(mgr, "sqlite")).To(Succeed())
+		Expect(Validate(mgr, "oracle")).To(MatchError(ContainSubstring("unknown dialect")))
+	})
+})
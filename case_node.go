@@ -0,0 +1,41 @@
+package rel
+
+// A CASE expression, the Case operand is optional
+type CaseNode struct {
+	Case       Visitable
+	Conditions []*WhenNode
+	Default    Visitable
+	BaseVisitable
+}
+
+type WhenNode BinaryNode
+
+func Case(visitables ...Visitable) *CaseNode {
+	node := &CaseNode{}
+	if len(visitables) > 0 {
+		node.Case = visitables[0]
+	}
+	return node
+}
+
+func (node *CaseNode) When(condition Visitable, result Visitable) *CaseNode {
+	node.Conditions = append(node.Conditions, &WhenNode{Left: condition, Right: result})
+	return node
+}
+
+func (node *CaseNode) Else(visitable Visitable) *CaseNode {
+	node.Default = visitable
+	return node
+}
+
+func (node *CaseNode) Desc() *DescendingNode {
+	return orderingDesc(node)
+}
+
+func (node *CaseNode) Asc() *AscendingNode {
+	return orderingAsc(node)
+}
+
+func (node *CaseNode) As(literal SqlLiteralNode) *AsNode {
+	return aliasPredicationAs(node, literal)
+}
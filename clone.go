@@ -0,0 +1,81 @@
+package rel
+
+import (
+	"reflect"
+)
+
+var engineType = reflect.TypeOf((*Engine)(nil)).Elem()
+
+// Clone returns a deep copy of any node or manager. Pointers shared
+// within the tree stay shared in the copy, engines are never copied.
+func Clone(node Visitable) Visitable {
+	if node == nil {
+		return nil
+	}
+	c := cloner{seen: make(map[clonedPointer]reflect.Value)}
+	return c.clone(reflect.ValueOf(node)).Interface().(Visitable)
+}
+
+type clonedPointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+type cloner struct {
+	seen map[clonedPointer]reflect.Value
+}
+
+func (c cloner) clone(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := clonedPointer{v.Pointer(), v.Type()}
+		if copied, ok := c.seen[key]; ok {
+			return copied
+		}
+		copied := reflect.New(v.Type().Elem())
+		c.seen[key] = copied
+		copied.Elem().Set(c.clone(v.Elem()))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() || v.Type() == engineType {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(c.clone(v.Elem()))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(c.clone(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMap(v.Type())
+		for _, key := range v.MapKeys() {
+			copied.SetMapIndex(key, c.clone(v.MapIndex(key)))
+		}
+		return copied
+	case reflect.Struct:
+		// copy the whole value first so unexported fields are kept
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := copied.Field(i)
+			if !field.CanSet() || field.Type() == engineType {
+				continue
+			}
+			field.Set(c.clone(v.Field(i)))
+		}
+		return copied
+	}
+	return v
+}
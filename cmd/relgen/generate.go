@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aackerman/rel"
+)
+
+const relImportPath = "github.com/aackerman/rel"
+
+var typeConstants = map[rel.ColumnType]string{
+	rel.TypeInteger:   "rel.TypeInteger",
+	rel.TypeFloat:     "rel.TypeFloat",
+	rel.TypeDecimal:   "rel.TypeDecimal",
+	rel.TypeText:      "rel.TypeText",
+	rel.TypeBoolean:   "rel.TypeBoolean",
+	rel.TypeTimestamp: "rel.TypeTimestamp",
+	rel.TypeDate:      "rel.TypeDate",
+	rel.TypeTime:      "rel.TypeTime",
+	rel.TypeBinary:    "rel.TypeBinary",
+	rel.TypeJSON:      "rel.TypeJSON",
+	rel.TypeUUID:      "rel.TypeUUID",
+}
+
+// words written in capitals in Go names
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// generate writes the source of a package with a struct per table
+func generate(catalog *rel.Catalog, pkg string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by relgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import %q\n", relImportPath)
+
+	identifiers := map[string]bool{}
+	for _, table := range catalog.Tables() {
+		writeTable(&buf, table, tableName(table, identifiers))
+	}
+	return format.Source(buf.Bytes())
+}
+
+// tableName picks the name X of the variable of a table, which is free when
+// X, its type XTable and constructor NewXTable are all unused. Tables with
+// the same name are told apart by their schema and database, or a number
+func tableName(table *rel.Table, identifiers map[string]bool) string {
+	base := goName(table.Name)
+	candidates := []string{
+		base,
+		goName(table.Schema + "_" + table.Name),
+		goName(table.Database + "_" + table.Schema + "_" + table.Name),
+	}
+	for _, name := range candidates {
+		if reserve(identifiers, name, name+"Table", "New"+name+"Table") {
+			return name
+		}
+	}
+	for n := 2; ; n++ {
+		name := base + strconv.Itoa(n)
+		if reserve(identifiers, name, name+"Table", "New"+name+"Table") {
+			return name
+		}
+	}
+}
+
+// reserve marks the names as used if none of them is
+func reserve(identifiers map[string]bool, names ...string) bool {
+	for _, name := range names {
+		if identifiers[name] {
+			return false
+		}
+	}
+	for _, name := range names {
+		identifiers[name] = true
+	}
+	return true
+}
+
+func writeTable(buf *bytes.Buffer, table *rel.Table, name string) {
+	typeName := name + "Table"
+	fmt.Fprintf(buf, "\n// %s is the %s table\n", typeName, table.Name)
+	fmt.Fprintf(buf, "type %s struct {\n*rel.Table\n}\n", typeName)
+	fmt.Fprintf(buf, "\nvar %s = New%s()\n", name, typeName)
+
+	fmt.Fprintf(buf, "\nfunc New%s() *%s {\n", typeName, typeName)
+	fmt.Fprintf(buf, "return &%s{rel.NewTable(%q,\n", typeName, table.Name)
+	if table.Database != "" {
+		fmt.Fprintf(buf, "rel.WithDatabase(%q),\n", table.Database)
+	}
+	if table.Schema != "" {
+		fmt.Fprintf(buf, "rel.WithSchema(%q),\n", table.Schema)
+	}
+	buf.WriteString("rel.WithColumns(\n")
+	for _, column := range table.Columns {
+		writeColumnDefinition(buf, column)
+	}
+	buf.WriteString("),\n)}\n}\n")
+
+	methods := map[string]bool{}
+	for _, column := range table.Columns {
+		method := goName(column.Name)
+		if tableMembers[method] {
+			method += "Column"
+		}
+		for methods[method] {
+			method += "_"
+		}
+		methods[method] = true
+		fmt.Fprintf(buf, "\n// %s is the %s.%s column\n", method, table.Name, column.Name)
+		fmt.Fprintf(buf, "func (t *%s) %s() *rel.AttributeNode {\nreturn t.Attr(%q)\n}\n", typeName, method, column.Name)
+	}
+}
+
+func writeColumnDefinition(buf *bytes.Buffer, column *rel.ColumnDefinition) {
+	fields := []string{fmt.Sprintf("Name: %q", column.Name)}
+	if constant, ok := typeConstants[column.Type]; ok {
+		fields = append(fields, "Type: "+constant)
+	}
+	if column.Nullable {
+		fields = append(fields, "Nullable: true")
+	}
+	if column.PrimaryKey {
+		fields = append(fields, "PrimaryKey: true")
+	}
+	if column.Default != nil {
+		visitor := &rel.ToSqlVisitor{Conn: rel.DefaultConnector{}}
+		fields = append(fields, "Default: rel.Sql("+strconv.Quote(visitor.Accept(column.Default))+")")
+	}
+	fmt.Fprintf(buf, "&rel.ColumnDefinition{%s},\n", strings.Join(fields, ", "))
+}
+
+// the names a column method would shadow or collide with
+var tableMembers = func() map[string]bool {
+	members := map[string]bool{"Table": true}
+	t := reflect.TypeOf(&rel.Table{})
+	for i := 0; i < t.NumMethod(); i++ {
+		members[t.Method(i).Name] = true
+	}
+	for i := 0; i < t.Elem().NumField(); i++ {
+		members[t.Elem().Field(i).Name] = true
+	}
+	return members
+}()
+
+// goName turns a table or column name such as user_id into UserID
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var buf bytes.Buffer
+	for _, word := range words {
+		if initialisms[strings.ToUpper(word)] {
+			buf.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		buf.WriteRune(unicode.ToUpper(runes[0]))
+		buf.WriteString(string(runes[1:]))
+	}
+	if buf.Len() == 0 || unicode.IsDigit([]rune(buf.String())[0]) {
+		return "X" + buf.String()
+	}
+	return buf.String()
+}
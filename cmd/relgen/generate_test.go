@@ -0,0 +1,164 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/aackerman/rel"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("relgen", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "relgen")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(name string, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(ioutil.WriteFile(path, []byte(contents), 0644)).To(Succeed())
+		return path
+	}
+
+	It("generates a struct with a method per column", func() {
+		catalog, err := rel.ParseSchema("postgresql", `CREATE TABLE public.users (
+			id bigint PRIMARY KEY,
+			email text NOT NULL,
+			order_count integer DEFAULT 0,
+			name text
+		)`)
+		Expect(err).NotTo(HaveOccurred())
+		source, err := generate(catalog, "models")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(source)).To(Equal(`// Code generated by relgen. DO NOT EDIT.
+
+package models
+
+import "github.com/aackerman/rel"
+
+// UsersTable is the users table
+type UsersTable struct {
+	*rel.Table
+}
+
+var Users = NewUsersTable()
+
+func NewUsersTable() *UsersTable {
+	return &UsersTable{rel.NewTable("users",
+		rel.WithSchema("public"),
+		rel.WithColumns(
+			&rel.ColumnDefinition{Name: "id", Type: rel.TypeInteger, PrimaryKey: true},
+			&rel.ColumnDefinition{Name: "email", Type: rel.TypeText},
+			&rel.ColumnDefinition{Name: "order_count", Type: rel.TypeInteger, Nullable: true, Default: rel.Sql("0")},
+			&rel.ColumnDefinition{Name: "name", Type: rel.TypeText, Nullable: true},
+		),
+	)}
+}
+
+// ID is the users.id column
+func (t *UsersTable) ID() *rel.AttributeNode {
+	return t.Attr("id")
+}
+
+// Email is the users.email column
+func (t *UsersTable) Email() *rel.AttributeNode {
+	return t.Attr("email")
+}
+
+// OrderCount is the users.order_count column
+func (t *UsersTable) OrderCount() *rel.AttributeNode {
+	return t.Attr("order_count")
+}
+
+// NameColumn is the users.name column
+func (t *UsersTable) NameColumn() *rel.AttributeNode {
+	return t.Attr("name")
+}
+`))
+	})
+
+	It("names tables of different schemas apart", func() {
+		catalog := rel.NewCatalog()
+		catalog.Declare("events", rel.WithSchema("audit"), rel.WithColumns(&rel.ColumnDefinition{Name: "id"}))
+		catalog.Declare("events", rel.WithSchema("public"), rel.WithColumns(&rel.ColumnDefinition{Name: "id"}))
+		source, err := generate(catalog, "models")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(source)).To(ContainSubstring("type EventsTable struct"))
+		Expect(string(source)).To(ContainSubstring("type PublicEventsTable struct"))
+	})
+
+	It("never generates the same identifier twice", func() {
+		catalog := rel.NewCatalog()
+		catalog.Declare("users", rel.WithColumns(&rel.ColumnDefinition{Name: "id"}))
+		catalog.Declare("users_table", rel.WithColumns(&rel.ColumnDefinition{Name: "id"}))
+		catalog.Declare("events", rel.WithSchema("public"), rel.WithColumns(&rel.ColumnDefinition{Name: "id"}))
+		catalog.Declare("public_events", rel.WithColumns(&rel.ColumnDefinition{Name: "id"}))
+		catalog.Declare("events", rel.WithColumns(&rel.ColumnDefinition{Name: "id"}))
+		source, err := generate(catalog, "models")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(source)).To(ContainSubstring("type UsersTable struct"))
+		Expect(string(source)).To(ContainSubstring("type UsersTable2Table struct"))
+		Expect(string(source)).To(ContainSubstring("type EventsTable struct"))
+		Expect(string(source)).To(ContainSubstring("type PublicEventsTable struct"))
+		Expect(string(source)).To(ContainSubstring("type PublicEvents2Table struct"))
+	})
+
+	It("reads JSON and YAML descriptions", func() {
+		catalog, err := loadSchema(write("schema.yaml", `
+tables:
+  - name: users
+    columns:
+      - {name: id, type: bigint, primary_key: true}
+      - {name: created_at, type: timestamp, nullable: true, default: CURRENT_TIMESTAMP}
+`), "postgresql")
+		Expect(err).NotTo(HaveOccurred())
+		users, ok := catalog.Table("users")
+		Expect(ok).To(BeTrue())
+		Expect(*users.Columns[1]).To(Equal(rel.ColumnDefinition{
+			Name: "created_at", Type: rel.TypeTimestamp, Nullable: true, Default: rel.Sql("CURRENT_TIMESTAMP"),
+		}))
+
+		catalog, err = loadSchema(write("schema.json", `{"tables": [{"name": "tags", "schema": "blog", "columns": [{"name": "label", "type": "varchar(20)"}]}]}`), "postgresql")
+		Expect(err).NotTo(HaveOccurred())
+		tags, ok := catalog.Table("blog.tags")
+		Expect(ok).To(BeTrue())
+		Expect(tags.Columns[0].Type).To(Equal(rel.TypeText))
+
+		_, err = loadSchema(write("schema.yaml", `tables: [{columns: []}]`), "postgresql")
+		Expect(err).To(MatchError("relgen: a table has no name"))
+	})
+
+	It("reads DDL scripts with the dialect", func() {
+		catalog, err := loadSchema(write("schema.sql", "CREATE TABLE `posts` (`id` int NOT NULL)"), "mysql")
+		Expect(err).NotTo(HaveOccurred())
+		posts, _ := catalog.Table("posts")
+		Expect(posts.Columns[0].Nullable).To(BeFalse())
+
+		_, err = loadSchema(write("schema.txt", ""), "mysql")
+		Expect(err).To(MatchError(ContainSubstring("cannot tell the format")))
+	})
+
+	It("reads SQLite databases", func() {
+		if _, err := exec.LookPath("sqlite3"); err != nil {
+			Skip("sqlite3 is not installed")
+		}
+		path := filepath.Join(dir, "app.db")
+		Expect(exec.Command("sqlite3", path, "CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL)").Run()).To(Succeed())
+		catalog, err := loadSchema(path, "postgresql")
+		Expect(err).NotTo(HaveOccurred())
+		users, ok := catalog.Table("users")
+		Expect(ok).To(BeTrue())
+		Expect(users.Columns).To(HaveLen(2))
+		Expect(users.Columns[1].Type).To(Equal(rel.TypeText))
+	})
+})
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/aackerman/rel"
+	"go.yaml.in/yaml/v3"
+)
+
+// every SQLite database file starts with this header
+const sqliteHeader = "SQLite format 3\x00"
+
+// schemaFile is the JSON or YAML description of a schema, types
+// are SQL types read with rel.ParseColumnType and defaults are SQL
+type schemaFile struct {
+	Tables []struct {
+		Name     string `yaml:"name"`
+		Schema   string `yaml:"schema"`
+		Database string `yaml:"database"`
+		Columns  []struct {
+			Name       string `yaml:"name"`
+			Type       string `yaml:"type"`
+			Nullable   bool   `yaml:"nullable"`
+			PrimaryKey bool   `yaml:"primary_key"`
+			Default    string `yaml:"default"`
+		} `yaml:"columns"`
+	} `yaml:"tables"`
+}
+
+// loadSchema reads a SQLite database, a DDL script or a JSON or
+// YAML description, DDL is parsed with the dialect
+func loadSchema(path string, dialect string) (*rel.Catalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte(sqliteHeader)):
+		return loadSQLite(path)
+	case strings.EqualFold(filepath.Ext(path), ".sql"):
+		return rel.ParseSchema(dialect, string(data))
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml":
+		return loadDescription(data)
+	}
+	return nil, fmt.Errorf("relgen: cannot tell the format of %s", path)
+}
+
+// JSON is read as YAML, which is a superset of it
+func loadDescription(data []byte) (*rel.Catalog, error) {
+	var file schemaFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	catalog := rel.NewCatalog()
+	for _, table := range file.Tables {
+		if table.Name == "" {
+			return nil, fmt.Errorf("relgen: a table has no name")
+		}
+		columns := []*rel.ColumnDefinition{}
+		for _, column := range table.Columns {
+			if column.Name == "" {
+				return nil, fmt.Errorf("relgen: a column of %s has no name", table.Name)
+			}
+			definition := &rel.ColumnDefinition{
+				Name:       column.Name,
+				Type:       rel.ParseColumnType(column.Type),
+				Nullable:   column.Nullable,
+				PrimaryKey: column.PrimaryKey,
+			}
+			if column.Default != "" {
+				definition.Default = rel.Sql(column.Default)
+			}
+			columns = append(columns, definition)
+		}
+		catalog.Declare(table.Name, rel.WithSchema(table.Schema), rel.WithDatabase(table.Database), rel.WithColumns(columns...))
+	}
+	return catalog, nil
+}
+
+// loadSQLite reads the CREATE TABLE statements stored in a SQLite
+// database with the sqlite3 command line shell
+func loadSQLite(path string) (*rel.Catalog, error) {
+	query := "SELECT sql || ';' FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'"
+	var stderr bytes.Buffer
+	cmd := exec.Command("sqlite3", "-readonly", path, query)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("relgen: sqlite3 failed: %v %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return rel.ParseSchema("sqlite", string(out))
+}
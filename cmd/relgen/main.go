@@ -0,0 +1,61 @@
+// Command relgen generates a Go package with a struct per table of a
+// schema. Each struct embeds *rel.Table and has a method per column
+// returning its *rel.AttributeNode, so column names are checked by
+// the compiler.
+//
+//	relgen -package models -o models/tables.go schema.sql
+//
+// The schema is a DDL script (.sql) parsed with the -dialect, a
+// JSON or YAML description (.json, .yaml or .yml) or a SQLite
+// database file, which is read with the sqlite3 command. A
+// description lists the tables with their columns:
+//
+//	tables:
+//	  - name: users
+//	    schema: public
+//	    columns:
+//	      - {name: id, type: bigint, primary_key: true}
+//	      - {name: email, type: varchar(255)}
+//	      - {name: deleted_at, type: timestamp, nullable: true}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	dialect := flag.String("dialect", "postgresql", "dialect of a DDL script: postgresql, mysql or sqlite")
+	pkg := flag.String("package", "tables", "name of the generated package")
+	output := flag.String("o", "", "file to write, standard output when empty")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: relgen [flags] schema\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	catalog, err := loadSchema(flag.Arg(0), *dialect)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	source, err := generate(catalog, *pkg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		os.Stdout.Write(source)
+		return
+	}
+	if err := ioutil.WriteFile(*output, source, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
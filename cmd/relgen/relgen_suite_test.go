@@ -0,0 +1,12 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"testing"
+)
+
+func TestRelgen(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Relgen Suite")
+}
@@ -0,0 +1,101 @@
+package rel
+
+import (
+	"time"
+)
+
+// ColumnValue are the Go types a Column can hold
+type ColumnValue interface {
+	~bool | ~string | ~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64 |
+		time.Time | []byte
+}
+
+// Column is an attribute holding values of type T, its predicates
+// take T values which are quoted by the connector instead of SqlLiteralNodes,
+// so comparing a Column[int] with a string does not compile
+type Column[T ColumnValue] struct {
+	*AttributeNode
+}
+
+func NewColumn[T ColumnValue](relation Visitable, name string) Column[T] {
+	return Column[T]{NewAttributeNode(relation, name)}
+}
+
+// attributeColumn lets the visitors render a Column as its attribute
+type attributeColumn interface {
+	attributeNode() *AttributeNode
+}
+
+func (c Column[T]) attributeNode() *AttributeNode {
+	return c.AttributeNode
+}
+
+// columnNode stores a Column given as a Visitable as its attribute,
+// the tree then only holds the node types the library knows
+func columnNode(node Visitable) Visitable {
+	if column, ok := node.(attributeColumn); ok {
+		return column.attributeNode()
+	}
+	return node
+}
+
+func (c Column[T]) Eq(value T) *EqualityNode {
+	return predicationEq(c.AttributeNode, NewValueNode(value))
+}
+
+func (c Column[T]) NotEq(value T) *NotEqualNode {
+	return predicationNotEq(c.AttributeNode, NewValueNode(value))
+}
+
+func (c Column[T]) Lt(value T) *LessThanNode {
+	return predicationLt(c.AttributeNode, NewValueNode(value))
+}
+
+func (c Column[T]) LtEq(value T) *LessThanOrEqualNode {
+	return predicationLtEq(c.AttributeNode, NewValueNode(value))
+}
+
+func (c Column[T]) Gt(value T) *GreaterThanNode {
+	return predicationGt(c.AttributeNode, NewValueNode(value))
+}
+
+func (c Column[T]) GtEq(value T) *GreaterThanOrEqualNode {
+	return predicationGtEq(c.AttributeNode, NewValueNode(value))
+}
+
+func (c Column[T]) In(values ...T) Visitable {
+	return predicationIn(c.AttributeNode, columnValues(values))
+}
+
+func (c Column[T]) NotIn(values ...T) Visitable {
+	return predicationNotIn(c.AttributeNode, columnValues(values))
+}
+
+func (c Column[T]) Between(low T, high T) *BetweenNode {
+	return &BetweenNode{
+		Left:  c.AttributeNode,
+		Right: &AndNode{Children: &[]Visitable{NewValueNode(low), NewValueNode(high)}},
+	}
+}
+
+func (c Column[T]) IsNull() *EqualityNode {
+	return predicationEq(c.AttributeNode, nil)
+}
+
+func (c Column[T]) IsNotNull() *NotEqualNode {
+	return predicationNotEq(c.AttributeNode, nil)
+}
+
+// EqColumn compares two columns holding the same type, as in joins
+func (c Column[T]) EqColumn(other Column[T]) *EqualityNode {
+	return predicationEq(c.AttributeNode, other.AttributeNode)
+}
+
+func columnValues[T ColumnValue](values []T) []Visitable {
+	visitables := []Visitable{}
+	for _, value := range values {
+		visitables = append(visitables, NewValueNode(value))
+	}
+	return visitables
+}
@@ -0,0 +1,84 @@
+package rel_test
+
+import (
+	"time"
+
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type userID int
+
+var _ = Describe("Column", func() {
+	users := NewTable("users")
+	id := NewColumn[userID](users, "id")
+	name := NewColumn[string](users, "name")
+	score := NewColumn[float64](users, "score")
+	createdAt := NewColumn[time.Time](users, "created_at")
+
+	It("quotes compared values", func() {
+		Expect(users.Where(name.Eq("O'Brien")).ToSql()).To(Equal(`SELECT FROM "users" WHERE "users"."name" = 'O''Brien'`))
+		Expect(users.Where(name.Eq("x' OR '1'='1")).ToSql()).To(HaveSuffix(`WHERE "users"."name" = 'x'' OR ''1''=''1'`))
+		Expect(users.Where(id.NotEq(7)).ToSql()).To(HaveSuffix(`WHERE "users"."id" != 7`))
+		Expect(users.Where(score.GtEq(1.5)).ToSql()).To(HaveSuffix(`WHERE "users"."score" >= 1.5`))
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		Expect(users.Where(createdAt.Lt(at)).ToSql()).To(HaveSuffix(`WHERE "users"."created_at" < '2024-01-02 03:04:05+00:00'`))
+	})
+
+	It("quotes values for the dialect", func() {
+		mysql := MysqlVisitor{Conn: DefaultConnector{}}
+		sqlite := SQLiteVisitor{Conn: DefaultConnector{}}
+		injection := users.Where(name.Eq(`x\' OR 1=1 -- `))
+		Expect(mysql.Accept(injection.Ast)).To(HaveSuffix(`WHERE "users"."name" = 'x\\'' OR 1=1 -- '`))
+		Expect(sqlite.Accept(injection.Ast)).To(HaveSuffix(`WHERE "users"."name" = 'x\'' OR 1=1 -- '`))
+
+		active := NewColumn[bool](users, "active")
+		Expect(mysql.Accept(users.Where(active.Eq(true)).Ast)).To(HaveSuffix(`WHERE "users"."active" = TRUE`))
+		Expect(sqlite.Accept(users.Where(active.Eq(false)).Ast)).To(HaveSuffix(`WHERE "users"."active" = 0`))
+		Expect(users.Where(active.Eq(true)).ToSql()).To(HaveSuffix(`WHERE "users"."active" = 't'`))
+	})
+
+	It("builds lists, ranges and null checks", func() {
+		Expect(users.Where(id.In(1, 2, 3)).ToSql()).To(HaveSuffix(`WHERE "users"."id" IN (1, 2, 3)`))
+		Expect(users.Where(name.NotIn("a", "b")).ToSql()).To(HaveSuffix(`WHERE "users"."name" NOT IN ('a', 'b')`))
+		Expect(users.Where(id.In()).ToSql()).To(HaveSuffix(`WHERE 1=0`))
+		Expect(users.Where(id.Between(10, 20)).ToSql()).To(HaveSuffix(`WHERE "users"."id" BETWEEN 10 AND 20`))
+		Expect(users.Where(name.IsNull()).ToSql()).To(HaveSuffix(`WHERE "users"."name" IS NULL`))
+		Expect(users.Where(name.IsNotNull()).ToSql()).To(HaveSuffix(`WHERE "users"."name" IS NOT NULL`))
+	})
+
+	It("is rendered as its attribute", func() {
+		posts := NewTable("posts")
+		author := NewColumn[userID](posts, "user_id")
+		mgr := users.Select(name).InnerJoin(posts).On(author.EqColumn(id)).Order(name.Desc())
+		Expect(mgr.ToSql()).To(Equal(`SELECT "users"."name" FROM "users" INNER JOIN "posts" ON "posts"."user_id" = "users"."id" ORDER BY "users"."name" DESC`))
+		Expect(NewFormatVisitor(RelEngine.Visitor()).Accept(users.Select(name))).To(Equal("SELECT \"users\".\"name\"\nFROM \"users\""))
+	})
+
+	It("is stored as its attribute", func() {
+		mgr := users.Select(name).Order(id).Group(name)
+		Expect(StructurallyEqual(mgr.Ast, users.Select(users.Attr("name")).Order(users.Attr("id")).Group(users.Attr("name")).Ast)).To(BeTrue())
+		Walk(mgr.Ast, func(node Visitable) bool {
+			Expect(node).NotTo(BeAssignableToTypeOf(name))
+			return true
+		})
+
+		data, err := EncodeJSON(users.Select(name).Where(users.Attr("parent_id").Eq(id)).Ast)
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := DecodeJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(RelEngine.Visitor().Accept(decoded)).To(Equal(`SELECT "users"."name" FROM "users" WHERE "users"."parent_id" = "users"."id"`))
+	})
+
+	It("is checked against declared columns", func() {
+		declared := NewTable("accounts", WithColumns(&ColumnDefinition{Name: "opened_at", Type: TypeTimestamp}))
+		opened := NewColumn[string](declared, "opened_at")
+		Expect(Lint(declared.Where(opened.Eq("yesterday")))[0].Message).To(Equal("timestamp column accounts.opened_at is compared with a text literal"))
+		Expect(Lint(declared.Where(NewColumn[time.Time](declared, "opened_at").Gt(time.Now())))).To(BeEmpty())
+	})
+
+	It("normalizes values for fingerprints", func() {
+		Expect(Fingerprint(users.Where(id.Eq(1))).Hash).To(Equal(Fingerprint(users.Where(id.Eq(2))).Hash))
+	})
+})
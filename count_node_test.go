@@ -10,7 +10,11 @@ var _ = Describe("CountNode", func() {
 	It("can be equal to other CountNode's", func() {
 		count1 := CountNode{Expressions: []Visitable{Sql("foo")}}
 		count2 := CountNode{Expressions: []Visitable{Sql("foo")}}
-		Expect(count1.Eq(count2)).To(BeTrue())
+		Expect(count1.IsEqual(count2)).To(BeTrue())
+	})
+
+	It("builds an equality with Eq", func() {
+		Expect(RelEngine.Visitor().Accept(Count().Eq(Sql(1)))).To(Equal(`COUNT(1) = 1`))
 	})
 
 	It("can use the As predication to be aliased", func() {
@@ -20,4 +24,50 @@ var _ = Describe("CountNode", func() {
 		expected := `SELECT COUNT("users"."id") AS foo FROM "users"`
 		Expect(sql).To(Equal(expected))
 	})
+
+	It("can count distinct values", func() {
+		table := NewTable("users")
+		mgr := table.Select(CountDistinct(table.Attr("email")))
+		expected := `SELECT COUNT(DISTINCT "users"."email") FROM "users"`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can filter the counted rows", func() {
+		table := NewTable("users")
+		mgr := table.Select(table.Attr("id").Count().Filter(table.Attr("active").Eq(&TrueNode{})))
+		expected := `SELECT COUNT("users"."id") FILTER (WHERE "users"."active" = TRUE) FROM "users"`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can render a string aggregate", func() {
+		table := NewTable("users")
+		mgr := table.Select(StringAgg(table.Attr("name"), &QuotedNode{Raw: ","}).Distinct())
+		expected := `SELECT STRING_AGG(DISTINCT "users"."name", ',') FROM "users"`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can render a case expression", func() {
+		table := NewTable("users")
+		node := Case().When(table.Attr("age").Lt(Sql(18)), &QuotedNode{Raw: "minor"}).Else(&QuotedNode{Raw: "adult"})
+		mgr := table.Select(node)
+		expected := `SELECT CASE WHEN "users"."age" < 18 THEN 'minor' ELSE 'adult' END FROM "users"`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can be compared in a having clause", func() {
+		table := NewTable("users")
+		mgr := table.Select(table.Attr("team_id")).Group(table.Attr("team_id"))
+		mgr.Having(Star().Count().Gt(Sql(5)))
+		expected := `SELECT "users"."team_id" FROM "users" GROUP BY "users"."team_id" HAVING COUNT(*) > 5`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("supports comparisons on other aggregates", func() {
+		table := NewTable("orders")
+		mgr := table.Select(table.Attr("user_id")).Group(table.Attr("user_id"))
+		mgr.Having(Sum(table.Attr("total")).GtEq(Sql(100)))
+		mgr.Order(Max(table.Attr("total")).Desc())
+		expected := `SELECT "orders"."user_id" FROM "orders" GROUP BY "orders"."user_id" HAVING SUM("orders"."total") >= 100 ORDER BY MAX("orders"."total") DESC`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
 })
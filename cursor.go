@@ -0,0 +1,103 @@
+package rel
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("rel: invalid cursor")
+
+// Cursor holds the values of the order expressions for the last row
+// of a page, it is used with SeekAfter and SeekBefore
+type Cursor []interface{}
+
+// Values converts the cursor into nodes that can be passed to SeekAfter
+// or SeekBefore. Strings are quoted by the dialect, numbers are used as literals
+func (c Cursor) Values() []Visitable {
+	visitables := make([]Visitable, len(c))
+	for i, value := range c {
+		switch t := value.(type) {
+		case nil:
+			visitables[i] = nil
+		case bool:
+			if t {
+				visitables[i] = &TrueNode{}
+			} else {
+				visitables[i] = &FalseNode{}
+			}
+		case json.Number:
+			visitables[i] = Sql(t.String())
+		case string:
+			visitables[i] = NewValueNode(t)
+		default:
+			visitables[i] = Sql(t)
+		}
+	}
+	return visitables
+}
+
+// CursorCodec encodes cursors into opaque tokens signed with HMAC-SHA256
+// so that a client cannot alter the values it sends back
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+func (c *CursorCodec) Encode(values ...interface{}) (string, error) {
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(payload))
+	buf.WriteString(".")
+	buf.WriteString(base64.RawURLEncoding.EncodeToString(c.sign(payload)))
+	return buf.String(), nil
+}
+
+func (c *CursorCodec) Decode(token string) (Cursor, error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	for _, value := range cursor {
+		switch value.(type) {
+		case nil, bool, json.Number, string:
+		default:
+			return nil, ErrInvalidCursor
+		}
+	}
+	return cursor, nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
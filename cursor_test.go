@@ -0,0 +1,58 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CursorCodec", func() {
+	var codec *CursorCodec
+
+	BeforeEach(func() {
+		codec = NewCursorCodec([]byte("secret"))
+	})
+
+	It("round trips cursor values", func() {
+		token, err := codec.Encode(10, "it's", true)
+		Expect(err).NotTo(HaveOccurred())
+		cursor, err := codec.Decode(token)
+		Expect(err).NotTo(HaveOccurred())
+		users := NewTable("users")
+		mgr := users.Select(Star()).Order(users.Attr("a"), users.Attr("b"), users.Attr("c"))
+		Expect(mgr.SeekAfter(cursor.Values()...)).To(Succeed())
+		expected := `SELECT * FROM "users" WHERE ("users"."a", "users"."b", "users"."c") > (10, 'it''s', TRUE) ORDER BY "users"."a", "users"."b", "users"."c"`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("quotes cursor strings for the dialect", func() {
+		token, _ := codec.Encode(`x\' OR 1=1 -- `)
+		cursor, err := codec.Decode(token)
+		Expect(err).NotTo(HaveOccurred())
+		users := NewTable("users")
+		mgr := users.Select(Star()).Order(users.Attr("name"))
+		Expect(mgr.SeekAfter(cursor.Values()...)).To(Succeed())
+		mysql := MysqlVisitor{Conn: DefaultConnector{}}
+		Expect(mysql.Accept(mgr.Ast)).To(ContainSubstring(`> 'x\\'' OR 1=1 -- '`))
+	})
+
+	It("refuses NULL cursor values", func() {
+		token, _ := codec.Encode(10, nil)
+		cursor, err := codec.Decode(token)
+		Expect(err).NotTo(HaveOccurred())
+		users := NewTable("users")
+		mgr := users.Select(Star()).Order(users.Attr("a"), users.Attr("b"))
+		Expect(mgr.SeekAfter(cursor.Values()...)).To(HaveOccurred())
+	})
+
+	It("rejects tampered tokens", func() {
+		forged, _ := NewCursorCodec([]byte("other")).Encode(11)
+		_, err := codec.Decode(forged)
+		Expect(err).To(Equal(ErrInvalidCursor))
+	})
+
+	It("rejects malformed tokens", func() {
+		_, err := codec.Decode("garbage")
+		Expect(err).To(Equal(ErrInvalidCursor))
+	})
+})
@@ -0,0 +1,222 @@
+package rel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseSchema declares the tables of the CREATE TABLE statements in
+// a DDL script, other statements are skipped. Column types are read
+// with ParseColumnType and defaults are kept as expressions.
+func ParseSchema(dialect string, ddl string) (catalog *Catalog, err error) {
+	engine := databaseEngine(dialect)
+	if engine == nil {
+		return nil, fmt.Errorf("rel: unknown dialect %q", dialect)
+	}
+	tokens, err := lex(dialect, ddl)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, dialect: dialect, engine: engine}
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(*ParseError)
+			if !ok {
+				panic(r)
+			}
+			catalog, err = nil, perr
+		}
+	}()
+	catalog = NewCatalog()
+	for p.peek().kind != tokenEOF {
+		if p.accept(";") {
+			continue
+		}
+		if table := p.parseCreateTable(); table != nil {
+			catalog.tables[qualifiedTableKey(table)] = table
+		}
+	}
+	return catalog, nil
+}
+
+// ParseColumnType returns the type of a column declared with the SQL
+// type, such as "varchar(255)" or "timestamp with time zone". Unknown
+// types follow the affinity rules of SQLite or are left empty.
+func ParseColumnType(sqlType string) ColumnType {
+	name := strings.ToLower(strings.TrimSpace(sqlType))
+	if i := strings.IndexAny(name, " ("); i >= 0 {
+		name = name[:i]
+	}
+	switch name {
+	case "int", "integer", "smallint", "bigint", "tinyint", "mediumint",
+		"int2", "int4", "int8", "serial", "smallserial", "bigserial":
+		return TypeInteger
+	case "real", "float", "double", "float4", "float8":
+		return TypeFloat
+	case "decimal", "numeric", "money":
+		return TypeDecimal
+	case "char", "character", "varchar", "nchar", "nvarchar", "text", "tinytext",
+		"mediumtext", "longtext", "citext", "clob", "string", "enum", "set":
+		return TypeText
+	case "bool", "boolean":
+		return TypeBoolean
+	case "timestamp", "timestamptz", "datetime":
+		return TypeTimestamp
+	case "date":
+		return TypeDate
+	case "time", "timetz":
+		return TypeTime
+	case "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob", "bytea":
+		return TypeBinary
+	case "json", "jsonb":
+		return TypeJSON
+	case "uuid":
+		return TypeUUID
+	case "interval", "point":
+		// would be read as integers by the affinity rules
+		return ""
+	}
+	upper := strings.ToUpper(sqlType)
+	switch {
+	case strings.Contains(upper, "INT"):
+		return TypeInteger
+	case strings.Contains(upper, "CHAR"), strings.Contains(upper, "CLOB"), strings.Contains(upper, "TEXT"):
+		return TypeText
+	case strings.Contains(upper, "BLOB"):
+		return TypeBinary
+	case strings.Contains(upper, "REAL"), strings.Contains(upper, "FLOA"), strings.Contains(upper, "DOUB"):
+		return TypeFloat
+	}
+	return ""
+}
+
+// parseCreateTable reads a CREATE TABLE statement or skips
+// any other statement and returns nil
+func (p *parser) parseCreateTable() *Table {
+	if !p.accept("CREATE") {
+		p.skipStatement()
+		return nil
+	}
+	p.accept("TEMP")
+	p.accept("TEMPORARY")
+	if !p.accept("TABLE") {
+		p.skipStatement()
+		return nil
+	}
+	p.accept("IF", "NOT", "EXISTS")
+	table := p.parseTableName(p.identifier())
+	if p.accept("AS") {
+		p.fail("CREATE TABLE AS is not supported")
+	}
+	p.expect("(")
+	for {
+		p.parseTableElement(table)
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.expect(")")
+	// table options such as ENGINE or WITHOUT ROWID
+	p.skipStatement()
+	return table
+}
+
+// skipStatement skips the tokens up to the end of the statement
+func (p *parser) skipStatement() {
+	for p.peek().kind != tokenEOF && !p.is(";") {
+		p.skipToken()
+	}
+}
+
+// skipToken skips a token or a parenthesized list of tokens
+func (p *parser) skipToken() {
+	if !p.accept("(") {
+		p.next()
+		return
+	}
+	for !p.accept(")") {
+		if p.peek().kind == tokenEOF {
+			p.fail("unexpected end of input")
+		}
+		p.skipToken()
+	}
+}
+
+// words which end the type of a column
+var columnConstraints = map[string]bool{
+	"CONSTRAINT": true, "NOT": true, "NULL": true, "PRIMARY": true, "DEFAULT": true,
+	"UNIQUE": true, "REFERENCES": true, "CHECK": true, "COLLATE": true, "GENERATED": true,
+	"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "COMMENT": true, "ON": true, "AS": true,
+}
+
+func (p *parser) parseTableElement(table *Table) {
+	if p.accept("CONSTRAINT") {
+		p.identifier()
+	}
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		p.expect("(")
+		for {
+			name := p.identifier()
+			column, ok := table.ColumnDefinition(name)
+			if !ok {
+				p.fail("unknown primary key column %s", name)
+			}
+			column.PrimaryKey, column.Nullable = true, false
+			p.skipTableElement()
+			if !p.accept(",") {
+				break
+			}
+		}
+		p.expect(")")
+		p.skipTableElement()
+		return
+	case p.is("UNIQUE"), p.is("FOREIGN"), p.is("CHECK"), p.is("FULLTEXT"), p.is("SPATIAL"),
+		p.is("EXCLUDE"), p.isIndex():
+		p.skipTableElement()
+		return
+	}
+
+	column := &ColumnDefinition{Name: p.identifier(), Nullable: true}
+	// the arguments of a type such as varchar(255) are skipped
+	words := []string{}
+	for p.peek().kind == tokenWord && !columnConstraints[strings.ToUpper(p.peek().text)] {
+		words = append(words, p.next().text)
+		if p.is("(") {
+			p.skipToken()
+		}
+	}
+	column.Type = ParseColumnType(strings.Join(words, " "))
+
+	for !p.is(",") && !p.is(")") && p.peek().kind != tokenEOF {
+		switch {
+		case p.accept("NOT", "NULL"):
+			column.Nullable = false
+		case p.accept("NULL"):
+			column.Nullable = true
+		case p.accept("PRIMARY", "KEY"):
+			column.PrimaryKey, column.Nullable = true, false
+		case p.accept("DEFAULT"):
+			column.Default = p.parseUnary()
+		default:
+			p.skipToken()
+		}
+	}
+	table.Columns = append(table.Columns, column)
+}
+
+// isIndex tells the MySQL KEY name (...) and INDEX name (...)
+// elements from columns named key or index
+func (p *parser) isIndex() bool {
+	if !p.is("KEY") && !p.is("INDEX") {
+		return false
+	}
+	return p.isAt(1, "(") || (p.isAt(2, "(") && ParseColumnType(p.peekAt(1).text) == "")
+}
+
+// skipTableElement skips the tokens up to the next element
+func (p *parser) skipTableElement() {
+	for !p.is(",") && !p.is(")") && p.peek().kind != tokenEOF {
+		p.skipToken()
+	}
+}
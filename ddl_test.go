@@ -0,0 +1,87 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseSchema", func() {
+	It("declares the tables of CREATE TABLE statements", func() {
+		catalog, err := ParseSchema("postgresql", `
+			CREATE EXTENSION IF NOT EXISTS pgcrypto;
+			CREATE TABLE IF NOT EXISTS public.users (
+				id bigserial PRIMARY KEY,
+				email varchar(255) NOT NULL UNIQUE,
+				active boolean NOT NULL DEFAULT true,
+				score numeric(10, 2) DEFAULT -1,
+				created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT users_email_check CHECK (email <> '')
+			);
+			CREATE INDEX users_email ON users (email);
+			CREATE TABLE memberships (
+				user_id integer REFERENCES users (id) ON DELETE CASCADE,
+				group_id integer,
+				role text DEFAULT 'member',
+				PRIMARY KEY (user_id, group_id)
+			)`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(catalog.Tables()).To(HaveLen(2))
+
+		users, ok := catalog.Table("public.users")
+		Expect(ok).To(BeTrue())
+		Expect(users.Schema).To(Equal("public"))
+		Expect(users.Columns).To(HaveLen(5))
+		Expect(*users.Columns[0]).To(Equal(ColumnDefinition{Name: "id", Type: TypeInteger, PrimaryKey: true}))
+		Expect(*users.Columns[1]).To(Equal(ColumnDefinition{Name: "email", Type: TypeText}))
+		Expect(users.Columns[2].Default).To(Equal(&TrueNode{}))
+		Expect(users.Columns[3].Type).To(Equal(TypeDecimal))
+		Expect(users.Columns[3].Nullable).To(BeTrue())
+		Expect(users.Columns[4].Type).To(Equal(TypeTimestamp))
+		Expect(users.Columns[4].Default).To(Equal(Sql("CURRENT_TIMESTAMP")))
+
+		memberships, ok := catalog.Table("memberships")
+		Expect(ok).To(BeTrue())
+		Expect(memberships.PrimaryKey()).To(HaveLen(2))
+		Expect(memberships.Columns[1].Nullable).To(BeFalse())
+		Expect(memberships.Columns[2].Default).To(Equal(&QuotedNode{Raw: "member"}))
+	})
+
+	It("reads MySQL and SQLite tables", func() {
+		catalog, err := ParseSchema("mysql", "CREATE TABLE `shop`.`orders` (\n"+
+			"`id` int unsigned NOT NULL AUTO_INCREMENT,\n"+
+			"`key` varchar(20),\n"+
+			"`total` double precision,\n"+
+			"PRIMARY KEY (`id`),\n"+
+			"KEY `orders_key` (`key`)\n"+
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4")
+		Expect(err).NotTo(HaveOccurred())
+		orders, ok := catalog.Table("shop.orders")
+		Expect(ok).To(BeTrue())
+		Expect(orders.Columns).To(HaveLen(3))
+		Expect(orders.Columns[1].Name).To(Equal("key"))
+		Expect(orders.Columns[2].Type).To(Equal(TypeFloat))
+		Expect(orders.PrimaryKey()[0].Name).To(Equal("id"))
+
+		catalog, err = ParseSchema("sqlite", `CREATE TABLE tags (id INTEGER PRIMARY KEY AUTOINCREMENT, name, payload BLOB) WITHOUT ROWID`)
+		Expect(err).NotTo(HaveOccurred())
+		tags, _ := catalog.Table("tags")
+		Expect(tags.Columns[1].Type).To(Equal(ColumnType("")))
+		Expect(tags.Columns[2].Type).To(Equal(TypeBinary))
+	})
+
+	It("reads column types", func() {
+		Expect(ParseColumnType("character varying(40)")).To(Equal(TypeText))
+		Expect(ParseColumnType("DATETIME")).To(Equal(TypeTimestamp))
+		Expect(ParseColumnType("jsonb")).To(Equal(TypeJSON))
+		Expect(ParseColumnType("UNSIGNED BIG INT")).To(Equal(TypeInteger))
+		Expect(ParseColumnType("interval")).To(Equal(ColumnType("")))
+	})
+
+	It("reports invalid statements", func() {
+		_, err := ParseSchema("postgresql", `CREATE TABLE users (id integer, PRIMARY KEY (uid))`)
+		Expect(err).To(MatchError(ContainSubstring("unknown primary key column uid")))
+		_, err = ParseSchema("postgresql", `CREATE TABLE users (id integer`)
+		Expect(err).To(HaveOccurred())
+	})
+})
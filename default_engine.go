@@ -1,8 +1,11 @@
 package rel
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 )
 
 var RelEngine Engine = &DefaultEngine{
@@ -29,6 +32,22 @@ func (c DefaultConnector) Quote(thing interface{}) string {
 		}
 	case int:
 		return strconv.Itoa(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case uint64:
+		return strconv.FormatUint(t, 10)
+	case float32:
+		return strconv.FormatFloat(float64(t), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(t, 'g', -1, 64)
+	case json.Number:
+		return t.String()
+	case string:
+		return "'" + strings.Replace(t, "'", "''", -1) + "'"
+	case []byte:
+		return fmt.Sprintf("X'%x'", t)
+	case time.Time:
+		return t.Format("'2006-01-02 15:04:05.999999-07:00'")
 	case nil:
 		return "NULL"
 	case *BindParamNode:
@@ -42,10 +61,11 @@ func (c DefaultConnector) Quote(thing interface{}) string {
 	}
 }
 
+// names are quoted with double quotes, a quote in a name is doubled
 func (c DefaultConnector) QuoteTableName(name string) string {
-	return "\"" + name + "\""
+	return "\"" + strings.Replace(name, "\"", "\"\"", -1) + "\""
 }
 
 func (c DefaultConnector) QuoteColumnName(name string) string {
-	return "\"" + name + "\""
+	return "\"" + strings.Replace(name, "\"", "\"\"", -1) + "\""
 }
@@ -45,3 +45,8 @@ func (mgr *DeleteManager) Where(visitable Visitable) *DeleteManager {
 	*mgr.Ast.Wheres = append(*mgr.Ast.Wheres, visitable)
 	return mgr
 }
+
+// Clone returns a deep copy, modifying it never affects mgr
+func (mgr *DeleteManager) Clone() *DeleteManager {
+	return Clone(mgr).(*DeleteManager)
+}
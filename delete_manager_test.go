@@ -24,4 +24,13 @@ var _ = Describe("DeleteManager", func() {
 			Expect(mgr.ToSql()).To(Equal(`DELETE FROM "users" WHERE "users"."id" = 1`))
 		})
 	})
+
+	It("can be cloned without leaking conditions", func() {
+		table := NewTable("users")
+		base := NewDeleteManager(RelEngine).From(table)
+		branch := base.Clone()
+		branch.Where(table.Attr("id").Eq(Sql(1)))
+		Expect(base.ToSql()).To(Equal(`DELETE FROM "users"`))
+		Expect(branch.ToSql()).To(Equal(`DELETE FROM "users" WHERE "users"."id" = 1`))
+	})
 })
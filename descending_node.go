@@ -15,5 +15,15 @@ func (node *DescendingNode) Direction() string {
 }
 
 func (node *DescendingNode) Reverse() *AscendingNode {
-	return &AscendingNode{Expr: node.Expr}
+	return &AscendingNode{Expr: node.Expr, Nulls: node.Nulls.reverse()}
+}
+
+func (node *DescendingNode) NullsFirst() *DescendingNode {
+	node.Nulls = NullsFirst
+	return node
+}
+
+func (node *DescendingNode) NullsLast() *DescendingNode {
+	node.Nulls = NullsLast
+	return node
 }
@@ -0,0 +1,389 @@
+package rel
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// An EmulationRule rewrites a node the dialect cannot render into an
+// equivalent node it can, Emulate returns nil when the rule does not
+// apply. The rewritten node must not match the rule again. Rules which
+// move orders into a derived table come before nulls-ordering so the
+// sort keys are built from the derived columns
+type EmulationRule struct {
+	Name    string
+	Emulate func(node Visitable) Visitable
+}
+
+// Emulations are the rules applied by the visitor of each dialect
+// unless its DisableEmulation option is set
+var Emulations = map[string][]EmulationRule{
+	"mysql": {
+		{Name: "full-outer-join", Emulate: emulateFullOuterJoin},
+		{Name: "distinct-on", Emulate: emulateDistinctOn},
+		{Name: "nulls-ordering", Emulate: emulateNullsOrdering},
+		{Name: "intersect-except", Emulate: emulateIntersectExcept},
+		// the largest LIMIT, as suggested by the MySQL manual
+		{Name: "offset-without-limit", Emulate: emulateOffsetWithoutLimit("18446744073709551615")},
+	},
+	"sqlite": {
+		{Name: "distinct-on", Emulate: emulateDistinctOn},
+		{Name: "values-column-list", Emulate: emulateValuesColumnList},
+	},
+}
+
+// emulator is implemented by the visitors which apply Emulations
+type emulator interface {
+	emulate(visitable Visitable) Visitable
+}
+
+func emulate(dialect string, visitable Visitable) Visitable {
+	for _, rule := range Emulations[dialect] {
+		if emulated := rule.Emulate(visitable); emulated != nil {
+			return emulated
+		}
+	}
+	return nil
+}
+
+func emulateDistinctOn(node Visitable) Visitable {
+	if stmt, ok := node.(*SelectStatementNode); ok {
+		if rewritten := rewriteDistinctOn(stmt); rewritten != nil {
+			return rewritten
+		}
+	}
+	return nil
+}
+
+func emulateOffsetWithoutLimit(limit string) func(Visitable) Visitable {
+	return func(node Visitable) Visitable {
+		if stmt, ok := node.(*SelectStatementNode); ok && stmt.Offset != nil && stmt.Limit == nil {
+			limited := *stmt
+			limited.Limit = &LimitNode{Expr: Sql(limit)}
+			return &limited
+		}
+		return nil
+	}
+}
+
+// SQLite names the columns of a VALUES list column1, column2, ... and
+// aliases have no column lists, so the columns are renamed in a subquery
+func emulateValuesColumnList(node Visitable) Visitable {
+	alias, ok := node.(*TableAliasNode)
+	if !ok || len(alias.Columns) == 0 {
+		return nil
+	}
+	relation := alias.Relation
+	if grouping, ok := relation.(*GroupingNode); ok && len(grouping.Expr) == 1 {
+		relation = grouping.Expr[0]
+	}
+	values, ok := relation.(*ValuesListNode)
+	if !ok {
+		return nil
+	}
+	selections := []Visitable{}
+	for i, column := range alias.Columns {
+		selections = append(selections, &AsNode{
+			Left:  Sql(fmt.Sprintf("column%d", i+1)),
+			Right: &UnqualifiedColumnNode{Expr: NewAttributeNode(nil, column)},
+		})
+	}
+	stmt := NewSelectStatementNode()
+	stmt.Cores[0].SetFrom(&GroupingNode{Expr: []Visitable{values}})
+	stmt.Cores[0].Selections = &selections
+	return &TableAliasNode{
+		Relation: &GroupingNode{Expr: []Visitable{stmt}},
+		Name:     alias.Name,
+		Quoted:   alias.Quoted,
+	}
+}
+
+// NULLS FIRST and NULLS LAST are emulated by sorting on whether the
+// expression is NULL before sorting on the expression itself
+func emulateNullsOrdering(node Visitable) Visitable {
+	switch node := node.(type) {
+	case *SelectStatementNode:
+		if orders := nullsSortKeys(node.Orders); orders != nil {
+			stmt := *node
+			stmt.Orders = orders
+			return &stmt
+		}
+	case *WindowNode:
+		if orders := nullsSortKeys(node.Orders); orders != nil {
+			window := *node
+			window.Orders = orders
+			return &window
+		}
+	case *NamedWindowNode:
+		if orders := nullsSortKeys(node.Orders); orders != nil {
+			window := *node
+			window.Orders = orders
+			return &window
+		}
+	}
+	return nil
+}
+
+// nullsSortKeys returns nil when no order places NULL values
+func nullsSortKeys(orders *[]Visitable) *[]Visitable {
+	if orders == nil {
+		return nil
+	}
+	keys := []Visitable{}
+	emulated := false
+	for _, order := range *orders {
+		switch o := order.(type) {
+		case *AscendingNode:
+			if o.Nulls != NullsDefault {
+				keys = append(keys, nullsSortKey(o.Expr, o.Nulls))
+				order, emulated = &AscendingNode{Expr: o.Expr}, true
+			}
+		case *DescendingNode:
+			if o.Nulls != NullsDefault {
+				keys = append(keys, nullsSortKey(o.Expr, o.Nulls))
+				order, emulated = &DescendingNode{Expr: o.Expr}, true
+			}
+		}
+		keys = append(keys, order)
+	}
+	if !emulated {
+		return nil
+	}
+	return &keys
+}
+
+// CASE WHEN expr IS NULL THEN 1 ELSE 0 END sorts NULL values last
+// in ascending order and first in descending order
+func nullsSortKey(expr Visitable, nulls NullsOrder) Visitable {
+	key := Case().When(&EqualityNode{Left: expr}, Sql(1)).Else(Sql(0))
+	if nulls == NullsFirst {
+		return key.Desc()
+	}
+	return key.Asc()
+}
+
+// FULL OUTER JOIN is emulated by the statement with the join as a LEFT
+// OUTER JOIN, UNION ALL the rows of a RIGHT OUTER JOIN without a match
+// on the left, found by a column of the left side compared in the join
+// condition being NULL. Common table expressions, orders and limits are
+// applied to the union, the order expressions are referenced by their
+// position in the projections. Grouped, distinct and aggregated
+// statements are not emulated, both halves would be aggregated apart
+func emulateFullOuterJoin(node Visitable) Visitable {
+	stmt, ok := node.(*SelectStatementNode)
+	if !ok || len(stmt.Cores) != 1 || stmt.Lock != nil {
+		return nil
+	}
+	core := stmt.Cores[0]
+	if core.Source == nil || core.isAggregate() || aggregatesRows(core.Selections) {
+		return nil
+	}
+	for i, join := range core.Source.Right {
+		full, ok := join.(*FullOuterJoinNode)
+		if !ok {
+			continue
+		}
+		key := leftJoinKey(full)
+		if key == nil {
+			return nil
+		}
+		left, right := core.copy(), core.copy()
+		left.Source.Right[i] = &OuterJoinNode{Left: full.Left, Right: full.Right}
+		right.Source.Right[i] = &RightOuterJoinNode{Left: full.Left, Right: full.Right}
+		wheres := []Visitable{}
+		if right.Wheres != nil {
+			wheres = append(wheres, *right.Wheres...)
+		}
+		wheres = append(wheres, &EqualityNode{Left: key})
+		right.Wheres = &wheres
+		union := &UnionAllNode{
+			Left:  &SelectStatementNode{Cores: []*SelectCoreNode{left}},
+			Right: &SelectStatementNode{Cores: []*SelectCoreNode{right}},
+		}
+		if stmt.With == nil && stmt.Orders == nil && stmt.Limit == nil && stmt.Offset == nil {
+			return union
+		}
+		outer := NewSelectStatementNode()
+		outer.With = stmt.With
+		outer.Cores[0].SetFrom(&TableAliasNode{Relation: union, Name: "rel_full_join", Quoted: true})
+		outer.Cores[0].Selections = &[]Visitable{Star()}
+		outer.Limit, outer.Offset = stmt.Limit, stmt.Offset
+		if stmt.Orders != nil {
+			orders := []Visitable{}
+			for _, order := range *stmt.Orders {
+				switch o := order.(type) {
+				case *AscendingNode:
+					order = &AscendingNode{Expr: unionColumn(core, o.Expr, o.Nulls), Nulls: o.Nulls}
+				case *DescendingNode:
+					order = &DescendingNode{Expr: unionColumn(core, o.Expr, o.Nulls), Nulls: o.Nulls}
+				default:
+					order = unionColumn(core, order, NullsDefault)
+				}
+				orders = append(orders, order)
+			}
+			outer.Orders = &orders
+		}
+		return outer
+	}
+	return nil
+}
+
+// aggregatesRows reports whether the projections have aggregate or
+// window functions, subqueries are not looked into
+func aggregatesRows(selections *[]Visitable) bool {
+	if selections == nil {
+		return false
+	}
+	found := false
+	for _, selection := range *selections {
+		Walk(selection, func(node Visitable) bool {
+			switch node.(type) {
+			case *CountNode, *SumNode, *MaxNode, *MinNode, *AvgNode, *StringAggNode,
+				*ArrayAggNode, *JsonAggNode, *BoolAndNode, *BoolOrNode, *OverNode:
+				found = true
+			case *SelectStatementNode, *SelectManager:
+				return false
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// leftJoinKey returns a column of the left side compared for equality
+// in the join condition, it is NULL only in rows without a match
+func leftJoinKey(join *FullOuterJoinNode) Visitable {
+	on, ok := join.Right.(*OnNode)
+	if !ok {
+		return nil
+	}
+	conditions := []Visitable{on.Expr}
+	for len(conditions) > 0 {
+		condition := conditions[0]
+		conditions = conditions[1:]
+		switch c := condition.(type) {
+		case *GroupingNode:
+			conditions = append(conditions, c.Expr...)
+		case *AndNode:
+			if c.Children != nil {
+				conditions = append(conditions, *c.Children...)
+			}
+		case *EqualityNode:
+			for _, side := range []Visitable{c.Left, c.Right} {
+				if attr, ok := side.(*AttributeNode); ok && !StructurallyEqual(attr.Relation, join.Left) {
+					return attr
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// unionColumn references an order expression by its position in the
+// projections, both sides of the union may project columns with the same
+// name. Expressions which are not projected and the sort keys of NULLS
+// FIRST and LAST, which cannot be positions, are referenced by name
+func unionColumn(core *SelectCoreNode, expr Visitable, nulls NullsOrder) Visitable {
+	if core.Selections != nil && nulls == NullsDefault {
+		for i, selection := range *core.Selections {
+			if as, ok := selection.(*AsNode); ok {
+				selection = as.Left
+			}
+			if reflect.DeepEqual(selection, expr) {
+				return Sql(i + 1)
+			}
+		}
+	}
+	return derivedColumn(expr)
+}
+
+// INTERSECT and EXCEPT are emulated with a correlated EXISTS comparing
+// the columns of both statements, NULL values compare as equal like
+// in set operations. Both statements need named columns
+func emulateIntersectExcept(node Visitable) Visitable {
+	switch node := node.(type) {
+	case *IntersectNode:
+		return existsSetOperation(node.Left, node.Right, false)
+	case *ExceptNode:
+		return existsSetOperation(node.Left, node.Right, true)
+	}
+	return nil
+}
+
+func existsSetOperation(left Visitable, right Visitable, except bool) Visitable {
+	left, right = unwrapSelectManager(left), unwrapSelectManager(right)
+	leftColumns, ok := statementColumns(left)
+	if !ok {
+		return nil
+	}
+	rightColumns, ok := statementColumns(right)
+	if !ok || len(leftColumns) != len(rightColumns) {
+		return nil
+	}
+
+	leftAlias := &TableAliasNode{Relation: &GroupingNode{Expr: []Visitable{left}}, Name: "rel_left", Quoted: true}
+	rightAlias := &TableAliasNode{Relation: &GroupingNode{Expr: []Visitable{right}}, Name: "rel_right", Quoted: true}
+
+	selections := []Visitable{}
+	conditions := []Visitable{}
+	for i := range leftColumns {
+		column := &AttributeNode{Name: leftColumns[i], Relation: leftAlias}
+		selections = append(selections, column)
+		conditions = append(conditions, &IsNotDistinctFromNode{
+			Left:  &AttributeNode{Name: rightColumns[i], Relation: rightAlias},
+			Right: column,
+		})
+	}
+
+	subquery := NewSelectStatementNode()
+	subquery.Cores[0].SetFrom(rightAlias)
+	subquery.Cores[0].Selections = &[]Visitable{Sql(1)}
+	subquery.Cores[0].Wheres = &[]Visitable{&AndNode{Children: &conditions}}
+
+	var exists Visitable = &ExistsNode{Expressions: []Visitable{subquery}}
+	if except {
+		exists = &NotNode{Expr: exists}
+	}
+
+	outer := NewSelectStatementNode()
+	outer.Cores[0].SetQuantifier = &DistinctNode{}
+	outer.Cores[0].SetFrom(leftAlias)
+	outer.Cores[0].Selections = &selections
+	outer.Cores[0].Wheres = &[]Visitable{exists}
+	return &GroupingNode{Expr: []Visitable{outer}}
+}
+
+func unwrapSelectManager(node Visitable) Visitable {
+	if mgr, ok := node.(*SelectManager); ok {
+		return mgr.Ast
+	}
+	return node
+}
+
+// statementColumns returns the names of the columns of a statement
+// selecting attributes or aliased expressions
+func statementColumns(node Visitable) ([]SqlLiteralNode, bool) {
+	stmt, ok := node.(*SelectStatementNode)
+	if !ok || len(stmt.Cores) != 1 || stmt.Cores[0].Selections == nil {
+		return nil, false
+	}
+	columns := []SqlLiteralNode{}
+	for _, selection := range *stmt.Cores[0].Selections {
+		switch s := selection.(type) {
+		case *AttributeNode:
+			columns = append(columns, s.Name)
+		case *AsNode:
+			switch alias := s.Right.(type) {
+			case SqlLiteralNode:
+				columns = append(columns, alias)
+			case *SqlLiteralNode:
+				columns = append(columns, *alias)
+			default:
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+	return columns, len(columns) > 0
+}
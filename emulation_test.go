@@ -0,0 +1,169 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Emulation", func() {
+	users := NewTable("users")
+	posts := NewTable("posts")
+	var mysql, sqlite Visitor
+
+	BeforeEach(func() {
+		mysql = MysqlVisitor{Conn: DefaultConnector{}}
+		sqlite = SQLiteVisitor{Conn: DefaultConnector{}}
+	})
+
+	It("emulates a full outer join with a union all of left and unmatched right joins", func() {
+		mgr := users.Select(users.Attr("id"), posts.Attr("id"))
+		mgr.FullOuterJoin(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		Expect(mysql.Accept(mgr.Ast)).To(Equal(`( SELECT "users"."id", "posts"."id" FROM "users" LEFT OUTER JOIN "posts" ON "posts"."user_id" = "users"."id" ` +
+			`UNION ALL SELECT "users"."id", "posts"."id" FROM "users" RIGHT OUTER JOIN "posts" ON "posts"."user_id" = "users"."id" WHERE "users"."id" IS NULL )`))
+		Expect(sqlite.Accept(mgr.Ast)).To(ContainSubstring(`FULL OUTER JOIN "posts"`))
+	})
+
+	It("orders and limits an emulated full outer join as a derived table", func() {
+		mgr := users.Select(users.Attr("name"))
+		mgr.FullOuterJoin(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr.Order(users.Attr("name").Asc()).Take(10)
+		sql := mysql.Accept(mgr.Ast)
+		Expect(sql).To(HavePrefix(`SELECT * FROM ( SELECT "users"."name" FROM "users" LEFT OUTER JOIN`))
+		Expect(sql).To(HaveSuffix(`RIGHT OUTER JOIN "posts" ON "posts"."user_id" = "users"."id" WHERE "users"."id" IS NULL ) "rel_full_join" ORDER BY 1 ASC LIMIT 10`))
+	})
+
+	It("orders an emulated full outer join by the position of columns with the same name", func() {
+		mgr := users.Select(users.Attr("id"), posts.Attr("id"))
+		mgr.FullOuterJoin(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr.Where(posts.Attr("published").Eq(Sql(1)))
+		mgr.Order(posts.Attr("id").Desc(), users.Attr("name"))
+		sql := mysql.Accept(mgr.Ast)
+		Expect(sql).To(ContainSubstring(`WHERE "posts"."published" = 1 AND "users"."id" IS NULL`))
+		Expect(sql).To(HaveSuffix(`) "rel_full_join" ORDER BY 2 DESC, "name"`))
+	})
+
+	It("does not emulate an aggregated full outer join", func() {
+		mgr := users.Select(Star().Count())
+		mgr.FullOuterJoin(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		Expect(mysql.Accept(mgr.Ast)).To(Equal(`SELECT COUNT(*) FROM "users" FULL OUTER JOIN "posts" ON "posts"."user_id" = "users"."id"`))
+		Expect(Validate(mgr, "mysql")).To(HaveOccurred())
+		mgr = users.Select(users.Attr("id")).Group(users.Attr("id"))
+		mgr.FullOuterJoin(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		Expect(mysql.Accept(mgr.Ast)).To(ContainSubstring(`FULL OUTER JOIN`))
+	})
+
+	It("applies common table expressions to an emulated full outer join", func() {
+		recent := NewTable("recent")
+		cte := posts.Select(Star()).Where(posts.Attr("id").Gt(Sql(10)))
+		mgr := users.Select(users.Attr("id"), recent.Attr("id"))
+		mgr.With(&AsNode{Left: recent, Right: &GroupingNode{Expr: []Visitable{cte.Ast}}})
+		mgr.FullOuterJoin(recent).On(recent.Attr("user_id").Eq(users.Attr("id")))
+		sql := mysql.Accept(mgr.Ast)
+		Expect(sql).To(HavePrefix(`WITH "recent" AS (SELECT * FROM "posts" WHERE "posts"."id" > 10) SELECT * FROM ( SELECT "users"."id", "recent"."id" FROM "users" LEFT OUTER JOIN "recent"`))
+		Expect(sql).To(HaveSuffix(`WHERE "users"."id" IS NULL ) "rel_full_join"`))
+	})
+
+	It("does not emulate a full outer join without a key of the left side", func() {
+		mgr := users.Select(users.Attr("id"))
+		mgr.FullOuterJoin(posts).On(Sql("TRUE"))
+		Expect(mysql.Accept(mgr.Ast)).To(ContainSubstring(`FULL OUTER JOIN "posts" ON TRUE`))
+	})
+
+	It("emulates distinct on with row numbers", func() {
+		mgr := posts.Select(posts.Attr("user_id"), posts.Attr("title").As(Sql("title")))
+		mgr.DistinctOn(posts.Attr("user_id")).Order(posts.Attr("user_id"), posts.Attr("created_at").Desc())
+		expected := `SELECT "user_id", title FROM (SELECT "posts"."user_id", "posts"."title" AS title, ROW_NUMBER() OVER (PARTITION BY "posts"."user_id" ORDER BY "posts"."user_id", "posts"."created_at" DESC) AS rel_row_number FROM "posts") subquery WHERE rel_row_number = 1 ORDER BY "user_id"`
+		Expect(mysql.Accept(mgr.Ast)).To(Equal(expected))
+		Expect(sqlite.Accept(mgr.Ast)).To(Equal(expected))
+	})
+
+	It("lists the declared columns of a distinct on star", func() {
+		declared := NewTable("posts", WithColumns(&ColumnDefinition{Name: "user_id"}, &ColumnDefinition{Name: "title"}))
+		mgr := declared.Select(Star()).DistinctOn(declared.Attr("user_id"))
+		Expect(mysql.Accept(mgr.Ast)).To(HavePrefix(`SELECT "user_id", "title" FROM (SELECT *, ROW_NUMBER() OVER`))
+	})
+
+	It("selects every column of the derived table when the columns cannot be listed", func() {
+		mgr := posts.Select(Star()).DistinctOn(posts.Attr("user_id"))
+		Expect(mysql.Accept(mgr.Ast)).To(Equal(`SELECT * FROM (SELECT *, ROW_NUMBER() OVER (PARTITION BY "posts"."user_id") AS rel_row_number FROM "posts") subquery WHERE rel_row_number = 1`))
+		mgr = posts.Select(posts.Attr("user_id"), Sql("lower(title)")).DistinctOn(posts.Attr("user_id"))
+		Expect(sqlite.Accept(mgr.Ast)).To(Equal(`SELECT * FROM (SELECT "posts"."user_id", lower(title), ROW_NUMBER() OVER (PARTITION BY "posts"."user_id") AS rel_row_number FROM "posts") subquery WHERE rel_row_number = 1`))
+	})
+
+	It("emulates intersect and except with exists", func() {
+		active := users.Select(users.Attr("id"))
+		authors := posts.Select(posts.Attr("user_id").As(Sql("author_id")))
+		mgr := users.Select().Intersect(active.Ast, authors.Ast)
+		Expect(mysql.Accept(mgr.Ast)).To(Equal(`(SELECT DISTINCT "rel_left"."id" FROM (SELECT "users"."id" FROM "users") "rel_left" ` +
+			`WHERE EXISTS (SELECT 1 FROM (SELECT "posts"."user_id" AS author_id FROM "posts") "rel_right" WHERE "rel_right"."author_id" <=> "rel_left"."id"))`))
+
+		mgr = users.Select().Except(active, authors)
+		Expect(mysql.Accept(mgr.Ast)).To(ContainSubstring(`WHERE NOT EXISTS (SELECT 1 FROM (SELECT "posts"."user_id" AS author_id FROM "posts") "rel_right"`))
+		Expect(sqlite.Accept(mgr.Ast)).To(ContainSubstring(" EXCEPT "))
+	})
+
+	It("keeps intersect when the columns have no names", func() {
+		mgr := users.Select().Intersect(users.Select(Star()).Ast, posts.Select(Star()).Ast)
+		Expect(mysql.Accept(mgr.Ast)).To(ContainSubstring(" INTERSECT "))
+	})
+
+	It("emulates nulls ordering with a CASE sort key", func() {
+		mgr := users.Select(users.Attr("id")).Order(users.Attr("age").Asc().NullsLast(), users.Attr("id").Desc())
+		Expect(mysql.Accept(mgr.Ast)).To(Equal(`SELECT "users"."id" FROM "users" ORDER BY ` +
+			`CASE WHEN "users"."age" IS NULL THEN 1 ELSE 0 END ASC, "users"."age" ASC, "users"."id" DESC`))
+		Expect(sqlite.Accept(mgr.Ast)).To(HaveSuffix(`ORDER BY "users"."age" ASC NULLS LAST, "users"."id" DESC`))
+
+		window := (&WindowNode{}).Order(users.Attr("age").Desc().NullsFirst())
+		Expect(mysql.Accept(window)).To(ContainSubstring(`ORDER BY CASE WHEN "users"."age" IS NULL THEN 1 ELSE 0 END DESC, "users"."age" DESC`))
+	})
+
+	It("adds a limit to an offset on MySQL and SQLite", func() {
+		mgr := users.Select(users.Attr("id")).Skip(5)
+		Expect(mysql.Accept(mgr.Ast)).To(HaveSuffix(`LIMIT 18446744073709551615 OFFSET 5`))
+		Expect(sqlite.Accept(mgr.Ast)).To(HaveSuffix(`LIMIT -1 OFFSET 5`))
+		Expect(mgr.Ast.Limit).To(BeNil())
+	})
+
+	It("renders the statement as written when emulation is disabled", func() {
+		mgr := users.Select(users.Attr("id")).Skip(5)
+		mgr.FullOuterJoin(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr.Order(users.Attr("id").Asc().NullsLast())
+		visitor := MysqlVisitor{Conn: DefaultConnector{}, DisableEmulation: true}
+		Expect(visitor.Accept(mgr.Ast)).To(Equal(`SELECT "users"."id" FROM "users" FULL OUTER JOIN "posts" ON "posts"."user_id" = "users"."id" ` +
+			`ORDER BY "users"."id" ASC NULLS LAST OFFSET 5`))
+		Expect(SQLiteVisitor{Conn: DefaultConnector{}, DisableEmulation: true}.Accept(mgr.Ast)).To(HaveSuffix(`LIMIT -1 OFFSET 5`))
+
+		err := Validate(mgr, "mysql")
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*LintError).Issues).To(HaveLen(3))
+		Expect(err.Error()).To(ContainSubstring("FULL OUTER JOIN is not supported"))
+		Expect(err.Error()).To(ContainSubstring("NULLS LAST is not supported"))
+		Expect(err.Error()).To(ContainSubstring("OFFSET without LIMIT is not supported"))
+	})
+
+	It("applies custom rules", func() {
+		rules := Emulations["sqlite"]
+		defer func() { Emulations["sqlite"] = rules }()
+		Emulations["sqlite"] = append(append([]EmulationRule{}, rules...), EmulationRule{
+			Name: "true-literal",
+			Emulate: func(node Visitable) Visitable {
+				if _, ok := node.(*TrueNode); ok {
+					return Sql(1)
+				}
+				return nil
+			},
+		})
+		Expect(sqlite.Accept(users.Where(&TrueNode{}).Ast)).To(HaveSuffix(`WHERE 1`))
+	})
+
+	It("is applied by the format visitor", func() {
+		mgr := users.Select(users.Attr("id")).Order(users.Attr("age").Asc().NullsFirst())
+		Expect(NewFormatVisitor(mysql).Accept(mgr)).To(Equal("SELECT \"users\".\"id\"\nFROM \"users\"\n" +
+			"ORDER BY\n  CASE WHEN \"users\".\"age\" IS NULL THEN 1 ELSE 0 END DESC,\n  \"users\".\"age\" ASC"))
+
+		parsed, err := Parse("postgresql", `SELECT * FROM "users" FULL JOIN "posts" ON "posts"."user_id" = "users"."id" ORDER BY "users"."id" DESC NULLS LAST`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.(*SelectManager).ToSql()).To(HaveSuffix(`FULL OUTER JOIN "posts" ON "posts"."user_id" = "users"."id" ORDER BY "users"."id" DESC NULLS LAST`))
+	})
+})
@@ -0,0 +1,156 @@
+package rel
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// StructurallyEqual reports whether two trees have the same structure. Pointer
+// identity and engines are ignored, nil and empty slices are the same.
+func StructurallyEqual(a Visitable, b Visitable) bool {
+	return equalValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// StructuralHash returns a structural hash of the tree that is stable across
+// processes, trees which are StructurallyEqual always share the same hash
+func StructuralHash(node Visitable) uint64 {
+	h := fnv.New64a()
+	hashValue(h, reflect.ValueOf(node))
+	return h.Sum64()
+}
+
+func equalValues(a reflect.Value, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Type() == engineType {
+			return true
+		}
+		return equalValues(a.Elem(), b.Elem())
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			if !equalValues(a.MapIndex(key), b.MapIndex(key)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		derived := derivedFields[a.Type()]
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).Type == engineType || derived[a.Type().Field(i).Name] {
+				continue
+			}
+			if !equalValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	// functions and channels only match when both are nil
+	return a.IsNil() && b.IsNil()
+}
+
+func hashValue(h hash.Hash64, v reflect.Value) {
+	if !v.IsValid() {
+		h.Write([]byte{0})
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() || v.Type() == engineType {
+			h.Write([]byte{0})
+			return
+		}
+		hashValue(h, v.Elem())
+	case reflect.Slice, reflect.Array:
+		hashUint(h, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+	case reflect.Map:
+		// map order is random, combine the entries in sorted order
+		sums := make([]uint64, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			entry := fnv.New64a()
+			hashValue(entry, key)
+			hashValue(entry, v.MapIndex(key))
+			sums = append(sums, entry.Sum64())
+		}
+		sort.Slice(sums, func(i, j int) bool { return sums[i] < sums[j] })
+		hashUint(h, uint64(len(sums)))
+		for _, sum := range sums {
+			hashUint(h, sum)
+		}
+	case reflect.Struct:
+		hashString(h, v.Type().String())
+		derived := derivedFields[v.Type()]
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Type == engineType || derived[v.Type().Field(i).Name] {
+				continue
+			}
+			hashValue(h, v.Field(i))
+		}
+	case reflect.Bool:
+		if v.Bool() {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{2})
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		hashUint(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		hashUint(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		hashUint(h, math.Float64bits(v.Float()))
+	case reflect.String:
+		hashString(h, v.String())
+	default:
+		h.Write([]byte{0})
+	}
+}
+
+func hashUint(h hash.Hash64, n uint64) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	h.Write(buf[:])
+}
+
+func hashString(h hash.Hash64, s string) {
+	hashUint(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
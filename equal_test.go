@@ -0,0 +1,54 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StructurallyEqual", func() {
+	build := func() *SelectManager {
+		users := NewTable("users")
+		return users.Select(users.Attr("id")).Where(users.Attr("age").Gt(Sql(21)))
+	}
+
+	It("considers separately built queries equal", func() {
+		Expect(StructurallyEqual(build().Ast, build().Ast)).To(BeTrue())
+		Expect(build().Ast.IsEqual(*build().Ast)).To(BeTrue())
+	})
+
+	It("detects a different structure", func() {
+		users := NewTable("users")
+		other := build().Where(users.Attr("active").Eq(Sql(true)))
+		Expect(StructurallyEqual(build().Ast, other.Ast)).To(BeFalse())
+		Expect(StructurallyEqual(users.Attr("id"), users.Attr("name"))).To(BeFalse())
+	})
+
+	It("compares trees using aliased tables", func() {
+		users := NewTable("users")
+		alias := users.Alias()
+		mgr := users.Select(alias.Attr("id")).InnerJoin(alias).On(alias.Attr("id").Eq(users.Attr("parent_id")))
+		Expect(StructurallyEqual(mgr.Ast, mgr.Clone().Ast)).To(BeTrue())
+		Expect(StructuralHash(mgr.Clone().Ast)).To(Equal(StructuralHash(mgr.Ast)))
+	})
+
+	It("treats nil and empty slices alike", func() {
+		Expect(StructurallyEqual(&GroupingNode{}, &GroupingNode{Expr: []Visitable{}})).To(BeTrue())
+	})
+})
+
+var _ = Describe("StructuralHash", func() {
+	It("is the same for equal trees", func() {
+		users := NewTable("users")
+		a := users.Select(users.Attr("id")).Where(users.Attr("id").Eq(Sql(1)))
+		b := users.Select(users.Attr("id")).Where(users.Attr("id").Eq(Sql(1)))
+		Expect(StructuralHash(a.Ast)).To(Equal(StructuralHash(b.Ast)))
+		Expect(StructuralHash(a.Clone().Ast)).To(Equal(StructuralHash(a.Ast)))
+	})
+
+	It("differs for different trees", func() {
+		users := NewTable("users")
+		Expect(StructuralHash(users.Attr("id").Eq(Sql(1)))).NotTo(Equal(StructuralHash(users.Attr("id").Eq(Sql(2)))))
+		Expect(StructuralHash(users.Attr("id").Lt(Sql(1)))).NotTo(Equal(StructuralHash(users.Attr("id").Gt(Sql(1)))))
+	})
+})
@@ -13,15 +13,17 @@ func NewEqualityNode(left Visitable, right Visitable) EqualityNode {
 	}
 }
 
+// Or and And keep a pointer to their copy of the node,
+// the visitors render *EqualityNode
 func (node EqualityNode) Or(other Visitable) *GroupingNode {
-	return &GroupingNode{Expr: []Visitable{&OrNode{Left: node, Right: other}}}
+	return &GroupingNode{Expr: []Visitable{&OrNode{Left: &node, Right: other}}}
 }
 
 func (node EqualityNode) And(other Visitable) *GroupingNode {
 	return &GroupingNode{
 		Expr: []Visitable{
 			&AndNode{
-				Children: &[]Visitable{node, other},
+				Children: &[]Visitable{&node, other},
 			},
 		},
 	}
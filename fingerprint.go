@@ -0,0 +1,210 @@
+package rel
+
+import (
+	"fmt"
+	"sort"
+)
+
+// QueryFingerprint identifies statements which only differ in their
+// literal values, the hash is the same for every dialect
+type QueryFingerprint struct {
+	Sql  string
+	Hash string
+}
+
+// Fingerprint normalizes a manager or node for grouping statements.
+// Literal values are replaced by placeholders, IN lists of literals are
+// collapsed to a single placeholder and AND-ed conditions are sorted.
+// Raw SQL literals in value positions are treated as values, raw SQL
+// on the left of a comparison is kept.
+func Fingerprint(node Visitable) QueryFingerprint {
+	normalized := Rewrite(node, normalizeLiterals)
+
+	// conditions are sorted bottom up so nested conditions are
+	// in order before their parents are hashed
+	nodes := []Visitable{}
+	Walk(normalized, func(node Visitable) bool {
+		if _, ok := node.(*BetweenNode); ok {
+			// the bounds of BETWEEN are not commutative
+			return false
+		}
+		nodes = append(nodes, node)
+		return true
+	})
+	for i := len(nodes) - 1; i >= 0; i-- {
+		sortConditions(nodes[i])
+	}
+
+	var sql string
+	if mgr, ok := normalized.(TreeManager); ok {
+		sql = mgr.ToSql()
+	} else {
+		sql = RelEngine.Visitor().Accept(normalized)
+	}
+	return QueryFingerprint{
+		Sql:  sql,
+		Hash: fmt.Sprintf("%016x", StructuralHash(normalized)),
+	}
+}
+
+func fingerprintPlaceholder() *BindParamNode {
+	return &BindParamNode{Raw: "?"}
+}
+
+func isLiteralValue(node Visitable) bool {
+	switch node.(type) {
+	case SqlLiteralNode, *SqlLiteralNode, QuotedNode, *QuotedNode, *BindParamNode, *ValueNode:
+		return true
+	}
+	return false
+}
+
+// normalizeOperand replaces a literal operand by a placeholder
+func normalizeOperand(node *Visitable) {
+	if isLiteralValue(*node) {
+		*node = fingerprintPlaceholder()
+	}
+}
+
+// normalizeSubject replaces the operand on the left of a comparison,
+// raw SQL there is an expression such as Sql("lower(name)") and kept
+func normalizeSubject(node *Visitable) {
+	switch (*node).(type) {
+	case SqlLiteralNode, *SqlLiteralNode:
+		return
+	}
+	normalizeOperand(node)
+}
+
+// normalizeLiterals replaces the literal operands of a node, identifiers
+// and aliases are never replaced as only value positions are changed
+func normalizeLiterals(node Visitable) Visitable {
+	switch node := node.(type) {
+	case *EqualityNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *NotEqualNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *GreaterThanNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *GreaterThanOrEqualNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *LessThanNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *LessThanOrEqualNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *MatchesNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *DoesNotMatchNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *IsDistinctFromNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *IsNotDistinctFromNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *InfixOperationNode:
+		normalizeSubject(&node.Left)
+		normalizeOperand(&node.Right)
+	case *AssignmentNode:
+		normalizeOperand(&node.Right)
+	case *BetweenNode:
+		normalizeSubject(&node.Left)
+		if bounds, ok := node.Right.(*AndNode); ok && bounds.Children != nil {
+			for i := range *bounds.Children {
+				normalizeOperand(&(*bounds.Children)[i])
+			}
+		}
+	case *InNode:
+		normalizeSubject(&node.Left)
+		node.Right = normalizeList(node.Right)
+	case *NotInNode:
+		normalizeSubject(&node.Left)
+		node.Right = normalizeList(node.Right)
+	case *LimitNode:
+		normalizeOperand(&node.Expr)
+	case *OffsetNode:
+		normalizeOperand(&node.Expr)
+	case *TopNode:
+		normalizeOperand(&node.Expr)
+	case *ValuesNode:
+		for _, row := range append([][]interface{}{node.Values}, node.Rows...) {
+			for i, value := range row {
+				if visitable, ok := value.(Visitable); !ok || isLiteralValue(visitable) {
+					row[i] = fingerprintPlaceholder()
+				}
+			}
+		}
+	case *ValuesListNode:
+		for _, row := range node.Rows {
+			for i, value := range row {
+				if visitable, ok := value.(Visitable); !ok || isLiteralValue(visitable) {
+					row[i] = fingerprintPlaceholder()
+				}
+			}
+		}
+	}
+	return node
+}
+
+// lists of literals of any length become a single placeholder
+func normalizeList(list []Visitable) []Visitable {
+	for _, item := range list {
+		if !isLiteralValue(item) {
+			return list
+		}
+	}
+	if len(list) == 0 {
+		return list
+	}
+	return []Visitable{fingerprintPlaceholder()}
+}
+
+// sortConditions orders AND-ed conditions by their structural hash
+func sortConditions(node Visitable) {
+	switch node := node.(type) {
+	case *AndNode:
+		if node.Children != nil {
+			sortByHash(*node.Children)
+		}
+	case *SelectCoreNode:
+		if node.Wheres != nil {
+			sortByHash(*node.Wheres)
+		}
+	case *UpdateStatementNode:
+		if node.Wheres != nil {
+			sortByHash(*node.Wheres)
+		}
+	case *DeleteStatementNode:
+		if node.Wheres != nil {
+			sortByHash(*node.Wheres)
+		}
+	}
+}
+
+func sortByHash(nodes []Visitable) {
+	hashes := make([]uint64, len(nodes))
+	for i, node := range nodes {
+		hashes[i] = StructuralHash(node)
+	}
+	sort.Sort(hashedNodes{nodes, hashes})
+}
+
+type hashedNodes struct {
+	nodes  []Visitable
+	hashes []uint64
+}
+
+func (h hashedNodes) Len() int           { return len(h.nodes) }
+func (h hashedNodes) Less(i, j int) bool { return h.hashes[i] < h.hashes[j] }
+func (h hashedNodes) Swap(i, j int) {
+	h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i]
+	h.hashes[i], h.hashes[j] = h.hashes[j], h.hashes[i]
+}
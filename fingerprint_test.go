@@ -0,0 +1,93 @@
+package rel_test
+
+import (
+	"strings"
+
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fingerprint", func() {
+	users := NewTable("users")
+
+	query := func(age int, ids []Visitable, reversed bool) *SelectManager {
+		mgr := users.Select(users.Attr("id"))
+		conditions := []Visitable{users.Attr("age").Gt(Sql(age)), users.Attr("id").In(ids)}
+		if reversed {
+			conditions[0], conditions[1] = conditions[1], conditions[0]
+		}
+		for _, condition := range conditions {
+			mgr.Where(condition)
+		}
+		return mgr.Take(age)
+	}
+
+	It("replaces literals and collapses IN lists", func() {
+		fingerprint := Fingerprint(query(21, []Visitable{Sql(1), Sql(2), Sql(3)}, false))
+		Expect(fingerprint.Sql).To(ContainSubstring(`"users"."age" > ?`))
+		Expect(fingerprint.Sql).To(ContainSubstring(`"users"."id" IN (?)`))
+		Expect(fingerprint.Sql).To(ContainSubstring(`LIMIT ?`))
+		Expect(fingerprint.Sql).NotTo(ContainSubstring("21"))
+		Expect(fingerprint.Hash).To(HaveLen(16))
+	})
+
+	It("is the same for statements which only differ in literals and condition order", func() {
+		a := Fingerprint(query(21, []Visitable{Sql(1), Sql(2), Sql(3)}, false))
+		b := Fingerprint(query(65, []Visitable{Sql(7)}, true))
+		Expect(b).To(Equal(a))
+
+		other := Fingerprint(users.Select(users.Attr("name")).Where(users.Attr("age").Gt(Sql(21))))
+		Expect(other.Hash).NotTo(Equal(a.Hash))
+	})
+
+	It("sorts nested AND conditions", func() {
+		a := users.Where(&AndNode{Children: &[]Visitable{users.Attr("a").Eq(Sql(1)), users.Attr("b").Eq(Sql(2))}})
+		b := users.Where(&AndNode{Children: &[]Visitable{users.Attr("b").Eq(Sql(3)), users.Attr("a").Eq(Sql(4))}})
+		Expect(Fingerprint(a)).To(Equal(Fingerprint(b)))
+	})
+
+	It("keeps the order of BETWEEN bounds and the original tree", func() {
+		mgr := users.Where(&BetweenNode{Left: users.Attr("age"), Right: &AndNode{Children: &[]Visitable{Sql(65), Sql(18)}}})
+		sql := mgr.ToSql()
+		Expect(Fingerprint(mgr).Sql).To(HaveSuffix(`WHERE "users"."age" BETWEEN ? AND ?`))
+		Expect(mgr.ToSql()).To(Equal(sql))
+	})
+
+	It("keeps raw SQL on the left of comparisons", func() {
+		mgr := users.Where(Sql("lower(name)").Eq(Sql("'amy'")))
+		Expect(Fingerprint(mgr).Sql).To(HaveSuffix(`WHERE lower(name) = ?`))
+		Expect(Fingerprint(users.Where(Sql("age").Gt(Sql(21)))).Sql).To(HaveSuffix(`WHERE age > ?`))
+	})
+
+	It("fingerprints self joins", func() {
+		parents := users.Alias()
+		mgr := users.Select(users.Attr("id")).InnerJoin(parents).On(parents.Attr("id").Eq(users.Attr("parent_id")))
+		mgr.Where(parents.Attr("name").Eq(Sql("'x'")))
+		fingerprint := Fingerprint(mgr)
+		Expect(fingerprint.Sql).To(HaveSuffix(`WHERE "users_2"."name" = ?`))
+		Expect(fingerprint.Hash).To(Equal(Fingerprint(mgr.Clone()).Hash))
+	})
+
+	It("is the same for every dialect", func() {
+		sql := `SELECT "users"."id" FROM "users" WHERE "users"."name" = 'x' AND "users"."id" IN (1, 2)`
+		hashes := map[string]bool{}
+		for _, dialect := range []string{"postgresql", "mysql", "sqlite"} {
+			input := sql
+			if dialect == "mysql" {
+				input = strings.Replace(sql, `"`, "`", -1)
+			}
+			mgr, err := Parse(dialect, input)
+			Expect(err).NotTo(HaveOccurred())
+			hashes[Fingerprint(mgr).Hash] = true
+		}
+		Expect(hashes).To(HaveLen(1))
+	})
+
+	It("normalizes inserted values and assignments", func() {
+		insert := NewInsertManager(RelEngine).Into(users).Insert(users.Attr("name"), "x")
+		Expect(Fingerprint(insert).Sql).To(Equal(`INSERT INTO "users" ("name") VALUES (?)`))
+		update := NewUpdateManager(RelEngine).Table(users).Set(users.Attr("name"), Sql("x"))
+		Expect(Fingerprint(update).Sql).To(Equal(`UPDATE "users" SET "name" = ?`))
+	})
+})
@@ -0,0 +1,452 @@
+package rel
+
+import (
+	"bytes"
+	"strings"
+)
+
+type KeywordCase int
+
+const (
+	UpperCaseKeywords KeywordCase = iota
+	LowerCaseKeywords
+)
+
+// FormatVisitor renders statements over several lines with one clause
+// per line. Expressions are rendered by the wrapped dialect visitor, the
+// output only differs from the dialect's SQL in whitespace and case.
+type FormatVisitor struct {
+	Dialect     Visitor
+	Indent      string
+	KeywordCase KeywordCase
+	depth       int
+}
+
+func NewFormatVisitor(dialect Visitor) *FormatVisitor {
+	return &FormatVisitor{Dialect: dialect, Indent: "  "}
+}
+
+func (v *FormatVisitor) Accept(visitable Visitable) string {
+	v.depth = 0
+	// top level managers are not wrapped in parentheses
+	switch node := visitable.(type) {
+	case *SelectManager:
+		visitable = node.Ast
+	case *MultiStatementManager:
+		visitable = node.Ast
+	}
+	return v.applyKeywordCase(v.Visit(visitable))
+}
+
+func (v *FormatVisitor) Visit(visitable Visitable) string {
+	if dialect, ok := v.Dialect.(emulator); ok {
+		if emulated := dialect.emulate(visitable); emulated != nil {
+			return v.Visit(emulated)
+		}
+	}
+	switch node := visitable.(type) {
+	case *SelectManager:
+		return "(" + v.nested(node.Ast) + v.newline() + ")"
+	case *MultiStatementManager:
+		return v.Visit(node.Ast)
+	case *InsertManager:
+		return v.Visit(node.Ast)
+	case *UpdateManager:
+		return v.Visit(node.Ast)
+	case *DeleteManager:
+		return v.Visit(node.Ast)
+	case *SelectStatementNode:
+		if dialect, ok := v.Dialect.(statementPreparer); ok {
+			node = dialect.prepareStatement(node)
+		}
+		return v.visitSelectStatementNode(node)
+	case *SelectCoreNode:
+		return v.visitSelectCoreNode(node)
+	case *UnionNode:
+		return v.visitSetOperation("UNION", node.Left, node.Right)
+	case *UnionAllNode:
+		return v.visitSetOperation("UNION ALL", node.Left, node.Right)
+	case *IntersectNode:
+		return v.visitSetOperation("INTERSECT", node.Left, node.Right)
+	case *ExceptNode:
+		return v.visitSetOperation("EXCEPT", node.Left, node.Right)
+	case *GroupingNode:
+		if len(node.Expr) == 1 && isSetOperation(node.Expr[0]) {
+			return v.Visit(node.Expr[0])
+		}
+		if len(node.Expr) == 1 && isFormattedQuery(node.Expr[0]) {
+			return "(" + v.nested(node.Expr[0]) + v.newline() + ")"
+		}
+	case *TableAliasNode:
+		return v.Visit(node.Relation) + SPACE + v.Dialect.QuoteTableName(node) +
+			visitationAliasColumns(v.Dialect, node.Columns)
+	case *AsNode:
+		// common table expressions
+		if isFormattedQuery(node.Right) {
+			return v.Visit(node.Left) + " AS " + v.Visit(node.Right)
+		}
+	case *WithNode:
+		return "WITH " + v.Visit(node.Expr)
+	case *WithRecursiveNode:
+		return "WITH RECURSIVE " + v.Visit(node.Expr)
+	case *InsertStatementNode:
+		return v.visitInsertStatementNode(node)
+	case *UpdateStatementNode:
+		return v.visitUpdateStatementNode(node)
+	case *DeleteStatementNode:
+		return v.visitDeleteStatementNode(node)
+	}
+	return v.Dialect.Visit(visitable)
+}
+
+func (v *FormatVisitor) QuoteTableName(relation Visitable) string {
+	return v.Dialect.QuoteTableName(relation)
+}
+
+func (v *FormatVisitor) QuoteColumnName(literal SqlLiteralNode) string {
+	return v.Dialect.QuoteColumnName(literal)
+}
+
+func (v *FormatVisitor) Quote(thing interface{}) string {
+	return v.Dialect.Quote(thing)
+}
+
+// queries which are laid out by the formatter instead of the dialect
+// statementPreparer is implemented by the visitors which change every
+// statement before rendering it
+type statementPreparer interface {
+	prepareStatement(node *SelectStatementNode) *SelectStatementNode
+}
+
+func isFormattedQuery(node Visitable) bool {
+	switch node.(type) {
+	case *SelectManager, *MultiStatementManager, *SelectStatementNode,
+		*UnionNode, *UnionAllNode, *IntersectNode, *ExceptNode:
+		return true
+	}
+	return false
+}
+
+func (v *FormatVisitor) newline() string {
+	return "\n" + strings.Repeat(v.Indent, v.depth)
+}
+
+// nested renders a query on its own lines one level deeper
+func (v *FormatVisitor) nested(node Visitable) string {
+	v.depth++
+	defer func() { v.depth-- }()
+	return v.newline() + v.Visit(node)
+}
+
+func (v *FormatVisitor) indented(fn func()) {
+	v.depth++
+	defer func() { v.depth-- }()
+	fn()
+}
+
+// list writes a single item on the clause line and several
+// items on their own lines one level deeper
+func (v *FormatVisitor) list(buf *bytes.Buffer, items []Visitable) {
+	if len(items) == 1 {
+		buf.WriteString(SPACE)
+		buf.WriteString(v.Visit(items[0]))
+		return
+	}
+	v.indented(func() {
+		for i, item := range items {
+			buf.WriteString(v.newline())
+			buf.WriteString(v.Visit(item))
+			if i < len(items)-1 {
+				buf.WriteString(",")
+			}
+		}
+	})
+}
+
+// conditions writes every AND-ed condition on its own line
+// at the current depth
+func (v *FormatVisitor) conditions(buf *bytes.Buffer, items []Visitable) {
+	for i, condition := range flattenAnd(items) {
+		if i > 0 {
+			buf.WriteString(v.newline())
+			buf.WriteString("AND ")
+		}
+		buf.WriteString(v.Visit(condition))
+	}
+}
+
+func flattenAnd(items []Visitable) []Visitable {
+	flattened := []Visitable{}
+	for _, item := range items {
+		if and, ok := item.(*AndNode); ok && and.Children != nil && len(*and.Children) > 0 {
+			flattened = append(flattened, flattenAnd(*and.Children)...)
+		} else {
+			flattened = append(flattened, item)
+		}
+	}
+	return flattened
+}
+
+func (v *FormatVisitor) visitSetOperation(operator string, left Visitable, right Visitable) string {
+	var buf bytes.Buffer
+	buf.WriteString("(")
+	buf.WriteString(v.nested(left))
+	buf.WriteString(v.newline())
+	buf.WriteString(operator)
+	buf.WriteString(v.nested(right))
+	buf.WriteString(v.newline())
+	buf.WriteString(")")
+	return buf.String()
+}
+
+func (v *FormatVisitor) visitSelectStatementNode(node *SelectStatementNode) string {
+	lines := []string{}
+
+	if node.With != nil {
+		lines = append(lines, v.Visit(node.With))
+	}
+
+	for _, core := range node.Cores {
+		if core != nil {
+			lines = append(lines, v.Visit(core))
+		}
+	}
+
+	if node.Orders != nil {
+		var buf bytes.Buffer
+		buf.WriteString("ORDER BY")
+		v.list(&buf, *node.Orders)
+		lines = append(lines, buf.String())
+	}
+
+	if node.Limit != nil {
+		lines = appendClause(lines, v.Dialect.Visit(node.Limit))
+	}
+
+	if node.Offset != nil {
+		lines = appendClause(lines, v.Dialect.Visit(node.Offset))
+	}
+
+	if node.Lock != nil {
+		lines = appendClause(lines, v.Dialect.Visit(node.Lock))
+	}
+
+	return strings.Join(lines, v.newline())
+}
+
+// appendClause skips the clauses which are rendered empty, such as a
+// raw lock without text
+func appendClause(lines []string, clause string) []string {
+	if clause == "" {
+		return lines
+	}
+	return append(lines, clause)
+}
+
+func (v *FormatVisitor) visitSelectCoreNode(node *SelectCoreNode) string {
+	var buf bytes.Buffer
+
+	buf.WriteString("SELECT")
+
+	if node.Top != nil {
+		buf.WriteString(SPACE)
+		buf.WriteString(v.Dialect.Visit(node.Top))
+	}
+
+	if node.SetQuantifier != nil {
+		buf.WriteString(SPACE)
+		buf.WriteString(v.Dialect.Visit(node.SetQuantifier))
+	}
+
+	if node.Selections != nil && len(*node.Selections) > 0 {
+		v.list(&buf, *node.Selections)
+	}
+
+	if node.Source != nil && node.Source.Left != nil {
+		// a *Table source is only emitted when it has a name
+		if t, ok := node.Source.Left.(*Table); !ok || (t != nil && t.Name != "") {
+			buf.WriteString(v.newline())
+			buf.WriteString("FROM ")
+			buf.WriteString(v.visitJoinSource(node.Source))
+		}
+	}
+
+	if node.Wheres != nil && len(*node.Wheres) > 0 {
+		buf.WriteString(v.newline())
+		buf.WriteString("WHERE ")
+		v.indented(func() { v.conditions(&buf, *node.Wheres) })
+	}
+
+	if node.Groups != nil && len(*node.Groups) > 0 {
+		buf.WriteString(v.newline())
+		buf.WriteString("GROUP BY")
+		v.list(&buf, *node.Groups)
+	}
+
+	if node.Having != nil {
+		buf.WriteString(v.newline())
+		buf.WriteString("HAVING ")
+		v.indented(func() { v.conditions(&buf, []Visitable{node.Having.Expr}) })
+	}
+
+	if node.Windows != nil && len(*node.Windows) > 0 {
+		buf.WriteString(v.newline())
+		buf.WriteString("WINDOW")
+		v.list(&buf, *node.Windows)
+	}
+
+	return buf.String()
+}
+
+// visitJoinSource writes every join on its own line with
+// its conditions aligned one level deeper
+func (v *FormatVisitor) visitJoinSource(node *JoinSource) string {
+	var buf bytes.Buffer
+	if node.Left != nil {
+		buf.WriteString(v.Visit(node.Left))
+	}
+	for _, join := range node.Right {
+		if comma, ok := join.(*CommaJoinNode); ok {
+			buf.WriteString(", ")
+			buf.WriteString(v.Visit(comma.Left))
+			continue
+		}
+		buf.WriteString(v.newline())
+		switch join := join.(type) {
+		case *InnerJoinNode:
+			buf.WriteString("INNER JOIN ")
+			buf.WriteString(v.Visit(join.Left))
+			v.joinConstraint(&buf, join.Right)
+		case *OuterJoinNode:
+			buf.WriteString("LEFT OUTER JOIN ")
+			buf.WriteString(v.Visit(join.Left))
+			v.joinConstraint(&buf, join.Right)
+		case *RightOuterJoinNode:
+			buf.WriteString("RIGHT OUTER JOIN ")
+			buf.WriteString(v.Visit(join.Left))
+			v.joinConstraint(&buf, join.Right)
+		case *FullOuterJoinNode:
+			buf.WriteString("FULL OUTER JOIN ")
+			buf.WriteString(v.Visit(join.Left))
+			v.joinConstraint(&buf, join.Right)
+		default:
+			buf.WriteString(strings.TrimSpace(v.Dialect.Visit(join)))
+		}
+	}
+	return buf.String()
+}
+
+func (v *FormatVisitor) joinConstraint(buf *bytes.Buffer, constraint Visitable) {
+	if constraint == nil {
+		return
+	}
+	on, ok := constraint.(*OnNode)
+	if !ok {
+		buf.WriteString(SPACE)
+		buf.WriteString(v.Dialect.Visit(constraint))
+		return
+	}
+	v.indented(func() {
+		buf.WriteString(v.newline())
+		buf.WriteString("ON ")
+		v.conditions(buf, []Visitable{on.Expr})
+	})
+}
+
+func (v *FormatVisitor) visitInsertStatementNode(node *InsertStatementNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("INSERT INTO ")
+	buf.WriteString(v.Visit(node.Relation))
+
+	if node.Columns != nil && len(*node.Columns) > 0 {
+		buf.WriteString(" (")
+		rangevals := []string{}
+		for _, column := range *node.Columns {
+			rangevals = append(rangevals, v.QuoteColumnName(column.Name))
+		}
+		buf.WriteString(strings.Join(rangevals, COMMA))
+		buf.WriteString(")")
+	}
+
+	if node.Values != nil {
+		buf.WriteString(v.newline())
+		buf.WriteString(v.Visit(node.Values))
+	}
+
+	return buf.String()
+}
+
+func (v *FormatVisitor) visitUpdateStatementNode(node *UpdateStatementNode) string {
+	var buf bytes.Buffer
+
+	wheres := updateWheres(node)
+
+	buf.WriteString("UPDATE ")
+	buf.WriteString(v.Visit(node.Relation))
+
+	if node.Values != nil && len(*node.Values) > 0 {
+		buf.WriteString(v.newline())
+		buf.WriteString("SET")
+		v.list(&buf, *node.Values)
+	}
+
+	if len(wheres) > 0 {
+		buf.WriteString(v.newline())
+		buf.WriteString("WHERE ")
+		v.indented(func() { v.conditions(&buf, wheres) })
+	}
+
+	return buf.String()
+}
+
+func (v *FormatVisitor) visitDeleteStatementNode(node *DeleteStatementNode) string {
+	var buf bytes.Buffer
+
+	buf.WriteString("DELETE FROM ")
+	buf.WriteString(v.Visit(node.Relation))
+
+	if node.Wheres != nil && len(*node.Wheres) > 0 {
+		buf.WriteString(v.newline())
+		buf.WriteString("WHERE ")
+		v.indented(func() { v.conditions(&buf, *node.Wheres) })
+	}
+
+	return buf.String()
+}
+
+// keywords written by the visitors, every other word keeps its case
+var formatKeywords = map[string]bool{
+	"ANY": true, "BINARY": true, "CUBE": true, "CURRENT": true, "DELETE": true,
+	"EXTRACT": true, "FALSE": true, "FIRST": true, "FOLLOWING": true, "GROUPING": true,
+	"INSERT": true, "INTO": true, "KEY": true, "LAST": true, "LOCKED": true, "MODE": true,
+	"NO": true, "NOWAIT": true, "OF": true, "PARTITION": true, "PRECEDING": true,
+	"RANGE": true, "RECURSIVE": true, "ROLLUP": true, "ROW": true, "ROWS": true,
+	"SETS": true, "SHARE": true, "SKIP": true, "TRUE": true, "UNBOUNDED": true,
+	"UPDATE": true,
+}
+
+func isFormatKeyword(word string) bool {
+	word = strings.ToUpper(word)
+	return reservedWords[word] || formatKeywords[word]
+}
+
+// applyKeywordCase changes the case of unquoted keywords, the SQL
+// is returned unchanged when it cannot be tokenized
+func (v *FormatVisitor) applyKeywordCase(sql string) string {
+	tokens, err := lex("", sql)
+	if err != nil {
+		return sql
+	}
+	out := []byte(sql)
+	for _, t := range tokens {
+		if t.kind != tokenWord || !isFormatKeyword(t.text) {
+			continue
+		}
+		word := strings.ToUpper(t.text)
+		if v.KeywordCase == LowerCaseKeywords {
+			word = strings.ToLower(t.text)
+		}
+		copy(out[t.pos:], word)
+	}
+	return string(out)
+}
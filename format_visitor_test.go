@@ -0,0 +1,165 @@
+package rel_test
+
+import (
+	"strings"
+
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FormatVisitor", func() {
+	withoutSpace := func(sql string) string {
+		return strings.Join(strings.Fields(sql), "")
+	}
+
+	It("writes one clause per line with aligned conditions", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		mgr := users.Select(users.Attr("id"), Star().Count())
+		mgr.Join(posts).On(posts.Attr("user_id").Eq(users.Attr("id")), posts.Attr("draft").Eq(Sql(false)))
+		mgr.Where(users.Attr("age").Gt(Sql(21)))
+		mgr.Where(users.Attr("active").Eq(Sql(true)))
+		mgr.Group(users.Attr("id"))
+		mgr.Having(Star().Count().Gt(Sql(5)))
+		mgr.Order(users.Attr("id").Desc())
+		mgr.Take(10).Skip(20)
+		expected := strings.Join([]string{
+			`SELECT`,
+			`  "users"."id",`,
+			`  COUNT(*)`,
+			`FROM "users"`,
+			`INNER JOIN "posts"`,
+			`  ON "posts"."user_id" = "users"."id"`,
+			`  AND "posts"."draft" = FALSE`,
+			`WHERE "users"."age" > 21`,
+			`  AND "users"."active" = TRUE`,
+			`GROUP BY "users"."id"`,
+			`HAVING COUNT(*) > 5`,
+			`ORDER BY "users"."id" DESC`,
+			`LIMIT 10`,
+			`OFFSET 20`,
+		}, "\n")
+		Expect(NewFormatVisitor(&PostgreSQLVisitor{Conn: DefaultConnector{}}).Accept(mgr)).To(Equal(expected))
+	})
+
+	It("uses the configured indentation and keyword case", func() {
+		users := NewTable("users")
+		mgr := users.Select(users.Attr("id"), users.Attr("name"))
+		mgr.Where(users.Attr("name").In([]Visitable{Sql(1), Sql(2)}))
+		v := NewFormatVisitor(&PostgreSQLVisitor{Conn: DefaultConnector{}})
+		v.Indent = "\t"
+		v.KeywordCase = LowerCaseKeywords
+		expected := "select\n\t\"users\".\"id\",\n\t\"users\".\"name\"\nfrom \"users\"\nwhere \"users\".\"name\" in (1, 2)"
+		Expect(v.Accept(mgr)).To(Equal(expected))
+	})
+
+	It("keeps quoted identifiers and strings that look like keywords", func() {
+		table := NewTable("select")
+		mgr := table.Select(table.Attr("from")).Where(table.Attr("from").Eq(&QuotedNode{Raw: "where"}))
+		v := NewFormatVisitor(&PostgreSQLVisitor{Conn: DefaultConnector{}})
+		v.KeywordCase = LowerCaseKeywords
+		Expect(v.Accept(mgr)).To(Equal("select \"select\".\"from\"\nfrom \"select\"\nwhere \"select\".\"from\" = 'where'"))
+	})
+
+	It("lays out subqueries, set operations and common table expressions", func() {
+		users := NewTable("users")
+		adults := users.Select(users.Attr("id")).Where(users.Attr("age").Gt(Sql(17)))
+		mgr := NewTable("").Select(Star())
+		mgr.Ctx.SetFrom(adults.As("adults"))
+		expected := strings.Join([]string{
+			`SELECT *`,
+			`FROM (`,
+			`  SELECT "users"."id"`,
+			`  FROM "users"`,
+			`  WHERE "users"."age" > 17`,
+			`) adults`,
+		}, "\n")
+		Expect(NewFormatVisitor(&PostgreSQLVisitor{Conn: DefaultConnector{}}).Accept(mgr)).To(Equal(expected))
+
+		cte := NewTable("adults").Select(Star())
+		cte.With(&AsNode{Left: NewTable("adults"), Right: adults})
+		expected = strings.Join([]string{
+			`WITH "adults" AS (`,
+			`  SELECT "users"."id"`,
+			`  FROM "users"`,
+			`  WHERE "users"."age" > 17`,
+			`)`,
+			`SELECT *`,
+			`FROM "adults"`,
+		}, "\n")
+		Expect(NewFormatVisitor(&PostgreSQLVisitor{Conn: DefaultConnector{}}).Accept(cte)).To(Equal(expected))
+
+		union := users.Select().Union(users.Select(users.Attr("id")).Ast, users.Select(users.Attr("id")).Ast)
+		expected = strings.Join([]string{
+			`(`,
+			`  SELECT "users"."id"`,
+			`  FROM "users"`,
+			`UNION`,
+			`  SELECT "users"."id"`,
+			`  FROM "users"`,
+			`)`,
+		}, "\n")
+		Expect(NewFormatVisitor(&PostgreSQLVisitor{Conn: DefaultConnector{}}).Accept(union)).To(Equal(expected))
+	})
+
+	It("leaves out the clauses which are rendered empty", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Lock(Sql(""))
+		Expect(NewFormatVisitor(&PostgreSQLVisitor{Conn: DefaultConnector{}}).Accept(mgr.Ast)).To(Equal("SELECT *\nFROM \"users\""))
+	})
+
+	It("renders SQL equivalent to every dialect", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		mgr := users.Select(users.Attr("id"), users.Attr("name"))
+		mgr.DistinctOn(users.Attr("name"))
+		mgr.Join(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr.OuterJoin(NewTable("teams")).On(users.Attr("team_id").Eq(NewTable("teams").Attr("id")))
+		mgr.Where(users.Attr("id").In([]Visitable{posts.Select(posts.Attr("user_id"))}))
+		mgr.Where(&GroupingNode{Expr: []Visitable{&OrNode{
+			Left:  users.Attr("age").Lt(Sql(18)),
+			Right: users.Attr("age").Gt(Sql(99)),
+		}}})
+		mgr.Order(users.Attr("name").Asc())
+		mgr.Skip(5)
+		update := NewUpdateManager(RelEngine).Table(users)
+		update.Set(users.Attr("name"), Sql("x")).Set(users.Attr("age"), Sql(30))
+		update.Where(users.Attr("id").Eq(Sql(1))).Order(users.Attr("id").Asc()).Take(1)
+		insert := NewInsertManager(RelEngine).Into(users).Insert(users.Attr("name"), "x")
+		values := NewValuesListNode([]interface{}{1, "a"}).As("v", "id", "name")
+		sources := Select(values.Attr("name")).From(values, users)
+		sources.FullOuterJoin(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		statements := []Visitable{
+			mgr.Ast,
+			sources.Ast,
+			update.Ast,
+			NewDeleteManager(RelEngine).From(users).Where(users.Attr("id").Eq(Sql(1))).Ast,
+			insert.Ast,
+		}
+		visitors := []Visitor{
+			&ToSqlVisitor{Conn: DefaultConnector{}},
+			&PostgreSQLVisitor{Conn: DefaultConnector{}},
+			MysqlVisitor{Conn: DefaultConnector{}},
+			SQLiteVisitor{Conn: DefaultConnector{}},
+		}
+		for _, visitor := range visitors {
+			for _, statement := range statements {
+				formatted := NewFormatVisitor(visitor).Accept(statement)
+				Expect(formatted).To(ContainSubstring("\n"))
+				Expect(withoutSpace(formatted)).To(Equal(withoutSpace(visitor.Accept(statement))))
+			}
+		}
+	})
+
+	It("produces SQL which parses back to the same statement", func() {
+		users := NewTable("users")
+		mgr := users.Select(users.Attr("id")).Where(users.Attr("age").Gt(Sql(21))).Where(users.Attr("age").Lt(Sql(65)))
+		mgr.Order(users.Attr("id").Asc())
+		v := NewFormatVisitor(&PostgreSQLVisitor{Conn: DefaultConnector{}})
+		v.KeywordCase = LowerCaseKeywords
+		parsed, err := Parse("postgresql", v.Accept(mgr))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed.(TreeManager).ToSql()).To(Equal(mgr.ToSql()))
+	})
+})
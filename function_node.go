@@ -1,33 +1,13 @@
 package rel
 
 type FunctionNode struct {
-	Expressions []Visitable
-	Alias       *SqlLiteralNode
-	Distinct    bool
+	Expressions    []Visitable
+	Alias          *SqlLiteralNode
+	DistinctValues bool      // set by Distinct on the aggregates
+	FilterExpr     Visitable // aggregate FILTER (WHERE ...) condition
 	BaseVisitable
 }
 
-type SumNode FunctionNode
-type MaxNode FunctionNode
-type MinNode FunctionNode
-type AvgNode FunctionNode
-
-func Sum(attr *AttributeNode) *SumNode {
-	return &SumNode{Expressions: []Visitable{attr}}
-}
-
-func Max(attr *AttributeNode) *MaxNode {
-	return &MaxNode{Expressions: []Visitable{attr}}
-}
-
-func Min(attr *AttributeNode) *MinNode {
-	return &MinNode{Expressions: []Visitable{attr}}
-}
-
-func Avg(attr *AttributeNode) *AvgNode {
-	return &AvgNode{Expressions: []Visitable{attr}}
-}
-
 func (node *FunctionNode) Over(visitable Visitable) *OverNode {
 	return windowPredicationOver(node, visitable)
 }
@@ -124,6 +104,62 @@ func (node *FunctionNode) NotInAll(visitableslices ...[]Visitable) Visitable {
 	return predicationNotInAll(node, visitableslices...)
 }
 
+func (node *FunctionNode) InQuery(mgr *SelectManager) *InNode {
+	return predicationInQuery(node, mgr)
+}
+
+func (node *FunctionNode) NotInQuery(mgr *SelectManager) *NotInNode {
+	return predicationNotInQuery(node, mgr)
+}
+
+func (node *FunctionNode) EqAnyQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAnyQuery(node, mgr)
+}
+
+func (node *FunctionNode) EqAllQuery(mgr *SelectManager) *EqualityNode {
+	return predicationEqAllQuery(node, mgr)
+}
+
+func (node *FunctionNode) NotEqAnyQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAnyQuery(node, mgr)
+}
+
+func (node *FunctionNode) NotEqAllQuery(mgr *SelectManager) *NotEqualNode {
+	return predicationNotEqAllQuery(node, mgr)
+}
+
+func (node *FunctionNode) LtAnyQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAnyQuery(node, mgr)
+}
+
+func (node *FunctionNode) LtAllQuery(mgr *SelectManager) *LessThanNode {
+	return predicationLtAllQuery(node, mgr)
+}
+
+func (node *FunctionNode) LtEqAnyQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAnyQuery(node, mgr)
+}
+
+func (node *FunctionNode) LtEqAllQuery(mgr *SelectManager) *LessThanOrEqualNode {
+	return predicationLtEqAllQuery(node, mgr)
+}
+
+func (node *FunctionNode) GtAnyQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAnyQuery(node, mgr)
+}
+
+func (node *FunctionNode) GtAllQuery(mgr *SelectManager) *GreaterThanNode {
+	return predicationGtAllQuery(node, mgr)
+}
+
+func (node *FunctionNode) GtEqAnyQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAnyQuery(node, mgr)
+}
+
+func (node *FunctionNode) GtEqAllQuery(mgr *SelectManager) *GreaterThanOrEqualNode {
+	return predicationGtEqAllQuery(node, mgr)
+}
+
 func (node *FunctionNode) NotEq(visitable Visitable) *NotEqualNode {
 	return predicationNotEq(node, visitable)
 }
@@ -136,6 +172,14 @@ func (node *FunctionNode) NotEqAll(visitables ...Visitable) *GroupingNode {
 	return predicationNotEqAll(node, visitables...)
 }
 
+func (node *FunctionNode) IsDistinctFrom(visitable Visitable) *IsDistinctFromNode {
+	return predicationIsDistinctFrom(node, visitable)
+}
+
+func (node *FunctionNode) IsNotDistinctFrom(visitable Visitable) *IsNotDistinctFromNode {
+	return predicationIsNotDistinctFrom(node, visitable)
+}
+
 func (node *FunctionNode) DoesNotMatch(literal SqlLiteralNode) *DoesNotMatchNode {
 	return predicationDoesNotMatch(node, literal)
 }
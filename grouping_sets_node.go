@@ -0,0 +1,35 @@
+package rel
+
+// Grouping elements which can be passed to SelectManager#Group
+// to compute subtotal rows
+type RollupNode struct {
+	Expressions []Visitable
+	BaseVisitable
+}
+
+type CubeNode RollupNode
+type GroupingSetsNode RollupNode
+
+func Rollup(visitables ...Visitable) *RollupNode {
+	return &RollupNode{Expressions: visitables}
+}
+
+func Cube(visitables ...Visitable) *CubeNode {
+	return &CubeNode{Expressions: visitables}
+}
+
+// GroupingSets accepts one grouping set per argument, use a Tuple
+// to group by several expressions and an empty Tuple for the grand total
+func GroupingSets(sets ...Visitable) *GroupingSetsNode {
+	return &GroupingSetsNode{Expressions: sets}
+}
+
+// Grouping creates a GROUPING(...) function which reports whether
+// the expressions are aggregated in a subtotal row
+func Grouping(visitables ...Visitable) *NamedFunctionNode {
+	name := Sql("GROUPING")
+	return &NamedFunctionNode{
+		Name:         &name,
+		FunctionNode: FunctionNode{Expressions: visitables},
+	}
+}
@@ -65,3 +65,8 @@ func (mgr *InsertManager) CreateValues(values []interface{}, columns []*Attribut
 		Columns: columns,
 	}
 }
+
+// Clone returns a deep copy, modifying it never affects mgr
+func (mgr *InsertManager) Clone() *InsertManager {
+	return Clone(mgr).(*InsertManager)
+}
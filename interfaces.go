@@ -40,12 +40,21 @@ func RegisterEngine(engine Engine) {
 }
 
 func RegisterDatabase(db string) {
+	if engine := databaseEngine(db); engine != nil {
+		RelEngine = engine
+	}
+}
+
+// databaseEngine returns the engine for a database name
+// accepted by RegisterDatabase or nil for unknown names
+func databaseEngine(db string) Engine {
 	switch db {
 	case "postgresql":
-		RelEngine = DefaultEngine{&PostgreSQLVisitor{Conn: DefaultConnector{}}}
+		return DefaultEngine{&PostgreSQLVisitor{Conn: DefaultConnector{}}}
 	case "sqlite":
-		RelEngine = DefaultEngine{&SQLiteVisitor{Conn: DefaultConnector{}}}
+		return DefaultEngine{&SQLiteVisitor{Conn: DefaultConnector{}}}
 	case "mysql":
-		RelEngine = DefaultEngine{&MysqlVisitor{Conn: DefaultConnector{}}}
+		return DefaultEngine{&MysqlVisitor{Conn: DefaultConnector{}}}
 	}
+	return nil
 }
@@ -8,3 +8,8 @@ type JoinSource struct {
 
 type InnerJoinNode JoinNode
 type OuterJoinNode JoinNode
+type RightOuterJoinNode JoinNode
+type FullOuterJoinNode JoinNode
+
+// CommaJoinNode is a further source listed after a comma in FROM
+type CommaJoinNode JoinNode
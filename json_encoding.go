@@ -0,0 +1,309 @@
+package rel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// JSONSchemaVersion is written with every encoded tree, trees
+// encoded with another version are rejected by DecodeJSON
+const JSONSchemaVersion = 1
+
+// node types which can be stored in a Visitable field
+var jsonNodeTypes = map[string]reflect.Type{}
+
+// values which are stored with their own JSON encoding,
+// times as RFC 3339 strings and bytes as base64
+var (
+	jsonTimeType   = reflect.TypeOf(time.Time{})
+	jsonBytesType  = reflect.TypeOf([]byte(nil))
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+)
+
+// fields which are back references or rebuilt from the rest of the
+// node, they are not stored, compared or hashed. Table.Aliases
+// points back at the table and would never end
+var derivedFields = map[reflect.Type]map[string]bool{
+	reflect.TypeOf(Table{}):         {"Aliases": true},
+	reflect.TypeOf(SelectManager{}): {"Ctx": true},
+}
+
+func init() {
+	nodes := []interface{}{
+		AndNode{}, ArrayAggNode{}, AsNode{}, AscendingNode{}, AttributeNode{},
+		AvgNode{}, BinNode{}, BinaryNode{}, BetweenNode{}, AssignmentNode{},
+		DoesNotMatchNode{}, GreaterThanNode{}, GreaterThanOrEqualNode{},
+		IsDistinctFromNode{}, IsNotDistinctFromNode{}, JoinNode{}, LessThanNode{},
+		LessThanOrEqualNode{}, MatchesNode{}, NotEqualNode{}, OrNode{}, UnionNode{},
+		UnionAllNode{}, IntersectNode{}, ExceptNode{}, BindParamNode{}, BoolAndNode{},
+		BoolOrNode{}, CaseNode{}, WhenNode{}, CountNode{}, DeleteManager{},
+		DeleteStatementNode{}, DescendingNode{}, DistinctNode{}, EqualityNode{},
+		InNode{}, NotInNode{}, ExistsNode{}, ExtractNode{}, FalseNode{},
+		FunctionNode{}, GroupingNode{}, RollupNode{}, CubeNode{}, GroupingSetsNode{},
+		InfixOperationNode{}, InsertManager{}, InsertStatementNode{}, JoinSource{},
+		InnerJoinNode{}, OuterJoinNode{}, RightOuterJoinNode{}, FullOuterJoinNode{},
+		CommaJoinNode{}, ValuesListNode{}, JsonAggNode{}, MaxNode{}, MinNode{},
+		MultiStatementManager{}, NamedFunctionNode{}, OverNode{}, QuotedNode{},
+		RowLockNode{}, SelectCoreNode{}, SelectManager{}, SelectStatementNode{},
+		SqlLiteralNode{}, StringAggNode{}, SumNode{}, Table{}, TableAliasNode{},
+		TrueNode{}, TupleNode{}, UnaryNode{}, GroupNode{}, HavingNode{}, LimitNode{},
+		NotNode{}, OffsetNode{}, OnNode{}, UsingNode{}, OrderingNode{}, TopNode{},
+		LockNode{}, DistinctOnNode{}, WithNode{}, WithRecursiveNode{}, RowsNode{},
+		RangeNode{}, CurrentRowNode{}, PrecedingNode{}, FollowingNode{}, AnyNode{},
+		AllNode{}, UnqualifiedColumnNode{}, UpdateManager{}, UpdateStatementNode{},
+		ValuesNode{}, ValueNode{}, WindowNode{}, NamedWindowNode{},
+	}
+	for _, node := range nodes {
+		t := reflect.TypeOf(node)
+		jsonNodeTypes[t.Name()] = t
+	}
+}
+
+type jsonDocument struct {
+	Version int             `json:"version"`
+	Node    json.RawMessage `json:"node"`
+}
+
+// a value stored in an interface field, Type is a node name
+// or the kind of a plain value such as "string" or "int"
+type jsonTagged struct {
+	Type    string          `json:"type"`
+	Pointer bool            `json:"pointer,omitempty"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// EncodeJSON stores a node or manager as JSON using the
+// versioned schema, engines are not stored
+func EncodeJSON(node Visitable) ([]byte, error) {
+	if node == nil {
+		return json.Marshal(jsonDocument{Version: JSONSchemaVersion, Node: json.RawMessage("null")})
+	}
+	encoded, err := encodeJSONTagged(reflect.ValueOf(node))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonDocument{Version: JSONSchemaVersion, Node: raw})
+}
+
+// DecodeJSON rebuilds a tree stored by EncodeJSON, decoded
+// tables and managers use the registered RelEngine
+func DecodeJSON(data []byte) (Visitable, error) {
+	var doc jsonDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Version != JSONSchemaVersion {
+		return nil, fmt.Errorf("rel: unsupported JSON schema version %d", doc.Version)
+	}
+	var holder Visitable
+	v := reflect.ValueOf(&holder).Elem()
+	if err := decodeJSONValue(doc.Node, v); err != nil {
+		return nil, err
+	}
+	return holder, nil
+}
+
+func encodeJSONTagged(v reflect.Value) (interface{}, error) {
+	// a Column is encoded as its attribute, it decodes as an *AttributeNode
+	if column, ok := v.Interface().(attributeColumn); ok {
+		v = reflect.ValueOf(column.attributeNode())
+	}
+	tagged := map[string]interface{}{}
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		tagged["pointer"] = true
+		t = t.Elem()
+		v = v.Elem()
+	}
+	if t.Implements(visitableType) || reflect.PtrTo(t).Implements(visitableType) {
+		if jsonNodeTypes[t.Name()] != t {
+			return nil, fmt.Errorf("rel: cannot encode unknown node %s", t)
+		}
+		tagged["type"] = t.Name()
+	} else if t == jsonTimeType {
+		tagged["type"] = "time"
+	} else if t == jsonBytesType {
+		tagged["type"] = "bytes"
+	} else if t == jsonNumberType {
+		tagged["type"] = "number"
+	} else {
+		switch t.Kind() {
+		case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int16,
+			reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+			reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+			tagged["type"] = t.Kind().String()
+		default:
+			return nil, fmt.Errorf("rel: cannot encode value of type %s", t)
+		}
+	}
+	value, err := encodeJSONValue(v)
+	if err != nil {
+		return nil, err
+	}
+	tagged["value"] = value
+	return tagged, nil
+}
+
+func encodeJSONValue(v reflect.Value) (interface{}, error) {
+	if v.Type() == jsonTimeType || v.Type() == jsonBytesType {
+		return v.Interface(), nil
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeJSONTagged(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return encodeJSONValue(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		values := make([]interface{}, v.Len())
+		for i := range values {
+			value, err := encodeJSONValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return values, nil
+	case reflect.Struct:
+		fields := map[string]interface{}{}
+		skipped := derivedFields[v.Type()]
+		for i := 0; i < v.NumField(); i++ {
+			info := v.Type().Field(i)
+			if info.PkgPath != "" || info.Type == engineType || skipped[info.Name] ||
+				info.Type == reflect.TypeOf(BaseVisitable{}) {
+				continue
+			}
+			value, err := encodeJSONValue(v.Field(i))
+			if err != nil {
+				return nil, err
+			}
+			if value != nil {
+				fields[info.Name] = value
+			}
+		}
+		return fields, nil
+	case reflect.Bool, reflect.String, reflect.Int, reflect.Int8, reflect.Int16,
+		reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return v.Interface(), nil
+	}
+	return nil, fmt.Errorf("rel: cannot encode value of type %s", v.Type())
+}
+
+// decodeJSONValue decodes raw into the settable value v
+func decodeJSONValue(raw json.RawMessage, v reflect.Value) error {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	if v.Type() == jsonTimeType || v.Type() == jsonBytesType {
+		return json.Unmarshal(raw, v.Addr().Interface())
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		var tagged jsonTagged
+		if err := json.Unmarshal(raw, &tagged); err != nil {
+			return err
+		}
+		t, ok := jsonNodeTypes[tagged.Type]
+		if !ok {
+			t, ok = jsonKinds[tagged.Type]
+		}
+		if !ok {
+			return fmt.Errorf("rel: cannot decode unknown type %q", tagged.Type)
+		}
+		value := reflect.New(t)
+		if err := decodeJSONValue(tagged.Value, value.Elem()); err != nil {
+			return err
+		}
+		if !tagged.Pointer {
+			value = value.Elem()
+		}
+		if !value.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("rel: cannot decode %s into %s", value.Type(), v.Type())
+		}
+		v.Set(value)
+		if mgr, ok := value.Interface().(*SelectManager); ok && mgr.Ast != nil {
+			if len(mgr.Ast.Cores) == 0 {
+				return fmt.Errorf("rel: cannot decode a SelectManager without cores")
+			}
+			mgr.Ctx = mgr.Ast.Cores[len(mgr.Ast.Cores)-1]
+		}
+	case reflect.Ptr:
+		value := reflect.New(v.Type().Elem())
+		if err := decodeJSONValue(raw, value.Elem()); err != nil {
+			return err
+		}
+		v.Set(value)
+	case reflect.Slice:
+		var values []json.RawMessage
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return err
+		}
+		slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := decodeJSONValue(value, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return err
+		}
+		for i := 0; i < v.NumField(); i++ {
+			info := v.Type().Field(i)
+			if info.Type == engineType && v.Field(i).CanSet() {
+				v.Field(i).Set(reflect.ValueOf(&RelEngine).Elem())
+				continue
+			}
+			if value, ok := fields[info.Name]; ok && v.Field(i).CanSet() {
+				if err := decodeJSONValue(value, v.Field(i)); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		value := reflect.New(v.Type())
+		if err := json.Unmarshal(raw, value.Interface()); err != nil {
+			return err
+		}
+		v.Set(value.Elem())
+	}
+	return nil
+}
+
+var jsonKinds = map[string]reflect.Type{
+	"bool":    reflect.TypeOf(false),
+	"string":  reflect.TypeOf(""),
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+	"time":    jsonTimeType,
+	"bytes":   jsonBytesType,
+	"number":  jsonNumberType,
+}
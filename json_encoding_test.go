@@ -0,0 +1,88 @@
+package rel_test
+
+import (
+	"time"
+
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON encoding", func() {
+	visitors := map[string]Visitor{
+		"ToSqlVisitor":      &ToSqlVisitor{Conn: DefaultConnector{}},
+		"PostgreSQLVisitor": &PostgreSQLVisitor{Conn: DefaultConnector{}},
+		"MysqlVisitor":      &MysqlVisitor{Conn: DefaultConnector{}},
+		"SQLiteVisitor":     &SQLiteVisitor{Conn: DefaultConnector{}},
+	}
+
+	build := func() *SelectManager {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		mgr := users.Select(users.Attr("id"), Sum(posts.Attr("score")).As(Sql("score")))
+		mgr.Join(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr.Where(users.Attr("name").Eq(Sql("'bob'")).And(users.Attr("age").Gt(Sql(21))))
+		mgr.Where(users.Attr("id").In([]Visitable{Sql(1), Sql(2)}))
+		mgr.Group(users.Attr("id"))
+		mgr.Having(Star().Count().Gt(Sql(5)))
+		mgr.Order(users.Attr("id").Desc())
+		mgr.Take(10).Skip(5)
+		return mgr
+	}
+
+	It("renders a decoded tree identically under every visitor", func() {
+		mgr := build()
+		data, err := EncodeJSON(mgr.Ast)
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := DecodeJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		for _, visitor := range visitors {
+			Expect(visitor.Accept(decoded)).To(Equal(visitor.Accept(mgr.Ast)))
+		}
+	})
+
+	It("decodes managers that can still be built upon", func() {
+		data, err := EncodeJSON(build())
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := DecodeJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		mgr := decoded.(*SelectManager)
+		Expect(mgr.ToSql()).To(Equal(build().ToSql()))
+		mgr.Where(Sql("1 = 1"))
+		Expect(mgr.ToSql()).To(ContainSubstring("AND 1 = 1"))
+	})
+
+	It("keeps raw insert values", func() {
+		users := NewTable("users")
+		mgr := NewInsertManager(RelEngine)
+		mgr.Insert(users.Attr("name"), "bob")
+		mgr.Insert(users.Attr("age"), 42)
+		data, err := EncodeJSON(mgr)
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := DecodeJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded.(*InsertManager).ToSql()).To(Equal(mgr.ToSql()))
+	})
+
+	It("keeps time and byte values", func() {
+		users := NewTable("users")
+		at := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+		mgr := users.Select(Star()).Where(NewColumn[time.Time](users, "created_at").Lt(at))
+		mgr.Where(NewColumn[[]byte](users, "digest").Eq([]byte{0xde, 0xad}))
+		data, err := EncodeJSON(mgr)
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := DecodeJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded.(*SelectManager).ToSql()).To(Equal(mgr.ToSql()))
+	})
+
+	It("rejects managers without cores", func() {
+		_, err := DecodeJSON([]byte(`{"version": 1, "node": {"type": "SelectManager", "pointer": true, "value": {"Ast": {"Cores": []}}}}`))
+		Expect(err).To(MatchError("rel: cannot decode a SelectManager without cores"))
+	})
+
+	It("rejects other schema versions", func() {
+		_, err := DecodeJSON([]byte(`{"version": 99, "node": null}`))
+		Expect(err).To(HaveOccurred())
+	})
+})
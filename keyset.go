@@ -0,0 +1,80 @@
+package rel
+
+import (
+	"fmt"
+)
+
+// seekPredicate builds the WHERE condition for keyset pagination from the
+// statement orders. When every column is ordered in the same direction a row
+// value comparison is used, mixed directions are expanded into
+// (a > x) OR (a = x AND b < y) ...
+// NULL cursor values are refused, a comparison with NULL matches no rows
+// and databases disagree on where NULLs are ordered
+func seekPredicate(orders *[]Visitable, values []Visitable, after bool) (Visitable, error) {
+	if orders == nil || len(*orders) == 0 {
+		return nil, fmt.Errorf("rel: seeking requires the statement to be ordered")
+	}
+	if len(*orders) != len(values) {
+		return nil, fmt.Errorf("rel: seeking requires %d cursor values, got %d", len(*orders), len(values))
+	}
+	for i, value := range values {
+		if isNullValue(value) {
+			return nil, fmt.Errorf("rel: cursor value %d is NULL, seek on non null columns", i+1)
+		}
+	}
+
+	exprs := make([]Visitable, len(*orders))
+	ascending := make([]bool, len(*orders))
+	for i, order := range *orders {
+		switch o := order.(type) {
+		case *AscendingNode:
+			exprs[i], ascending[i] = o.Expr, true
+		case *DescendingNode:
+			exprs[i], ascending[i] = o.Expr, false
+		default:
+			exprs[i], ascending[i] = o, true
+		}
+	}
+
+	// a column ordered ascending follows the cursor when it is greater
+	forward := func(i int) bool {
+		return ascending[i] == after
+	}
+
+	uniform := true
+	for i := range ascending {
+		if ascending[i] != ascending[0] {
+			uniform = false
+		}
+	}
+
+	if uniform && len(exprs) > 1 {
+		if forward(0) {
+			return Tuple(exprs...).Gt(Tuple(values...)), nil
+		}
+		return Tuple(exprs...).Lt(Tuple(values...)), nil
+	}
+
+	alternatives := make([]Visitable, len(exprs))
+	for i := range exprs {
+		children := []Visitable{}
+		for j := 0; j < i; j++ {
+			children = append(children, &EqualityNode{Left: exprs[j], Right: values[j]})
+		}
+		if forward(i) {
+			children = append(children, &GreaterThanNode{Left: exprs[i], Right: values[i]})
+		} else {
+			children = append(children, &LessThanNode{Left: exprs[i], Right: values[i]})
+		}
+		if len(children) == 1 {
+			alternatives[i] = children[0]
+		} else {
+			alternatives[i] = &GroupingNode{Expr: []Visitable{&AndNode{Children: &children}}}
+		}
+	}
+
+	if len(alternatives) == 1 {
+		return alternatives[0], nil
+	}
+	return predicationGroupAny(nil, alternatives...), nil
+}
@@ -0,0 +1,244 @@
+package rel
+
+import (
+	"fmt"
+	"strings"
+)
+
+type LintSeverity int
+
+const (
+	LintSeverityWarning LintSeverity = iota
+	LintSeverityError
+)
+
+func (s LintSeverity) String() string {
+	if s == LintSeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// LintContext describes where a node was found
+type LintContext struct {
+	// Root is the manager or node passed to Lint
+	Root Visitable
+	// Subquery is set for nodes of a statement nested in another
+	Subquery bool
+}
+
+// A LintRule is called for every node of a tree, Check returns
+// an empty message when the node is fine
+type LintRule struct {
+	Name     string
+	Severity LintSeverity
+	Check    func(node Visitable, ctx LintContext) string
+}
+
+type LintIssue struct {
+	Rule     string
+	Severity LintSeverity
+	Message  string
+	Node     Visitable
+}
+
+func (issue LintIssue) String() string {
+	return fmt.Sprintf("%s: %s (%s)", issue.Severity, issue.Message, issue.Rule)
+}
+
+type LintIssues []LintIssue
+
+// Err returns a *LintError when any issue is an error so Lint
+// can be used as a gate before running a statement
+func (issues LintIssues) Err() error {
+	errors := LintIssues{}
+	for _, issue := range issues {
+		if issue.Severity == LintSeverityError {
+			errors = append(errors, issue)
+		}
+	}
+	if len(errors) == 0 {
+		return nil
+	}
+	return &LintError{Issues: errors}
+}
+
+type LintError struct {
+	Issues LintIssues
+}
+
+func (e *LintError) Error() string {
+	messages := []string{}
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.String())
+	}
+	return "rel: " + strings.Join(messages, "; ")
+}
+
+// DefaultLintRules are used by Lint when no rules are given,
+// append to a copy of them to add rules
+var DefaultLintRules = []LintRule{
+	{Name: "missing-where", Severity: LintSeverityError, Check: lintMissingWhere},
+	{Name: "not-in-null", Severity: LintSeverityError, Check: lintNotInNull},
+	{Name: "leading-wildcard", Severity: LintSeverityWarning, Check: lintLeadingWildcard},
+	{Name: "offset-without-order", Severity: LintSeverityWarning, Check: lintOffsetWithoutOrder},
+	{Name: "subquery-limit-without-order", Severity: LintSeverityWarning, Check: lintSubqueryLimitWithoutOrder},
+	{Name: "cartesian-join", Severity: LintSeverityWarning, Check: lintCartesianJoin},
+	{Name: "unknown-column", Severity: LintSeverityError, Check: lintUnknownColumn},
+	{Name: "type-mismatch", Severity: LintSeverityError, Check: lintTypeMismatch},
+}
+
+// Lint checks every node of a manager or node with the rules,
+// or with DefaultLintRules when no rules are given
+func Lint(node Visitable, rules ...LintRule) LintIssues {
+	if len(rules) == 0 {
+		rules = DefaultLintRules
+	}
+	l := &linter{rules: rules, root: node, issues: LintIssues{}}
+	l.lint(node, false)
+	return l.issues
+}
+
+type linter struct {
+	rules  []LintRule
+	root   Visitable
+	issues LintIssues
+}
+
+// lint walks a statement, nested statements are linted as subqueries
+func (l *linter) lint(node Visitable, subquery bool) {
+	top := node
+	if mgr, ok := node.(*SelectManager); ok {
+		top = mgr.Ast
+	}
+	ctx := LintContext{Root: l.root, Subquery: subquery}
+	Walk(node, func(child Visitable) bool {
+		if stmt, ok := child.(*SelectStatementNode); ok && stmt != top {
+			l.lint(stmt, true)
+			return false
+		}
+		for _, rule := range l.rules {
+			if message := rule.Check(child, ctx); message != "" {
+				l.issues = append(l.issues, LintIssue{
+					Rule:     rule.Name,
+					Severity: rule.Severity,
+					Message:  message,
+					Node:     child,
+				})
+			}
+		}
+		return true
+	})
+}
+
+func lintMissingWhere(node Visitable, ctx LintContext) string {
+	switch node := node.(type) {
+	case *UpdateStatementNode:
+		if node.Wheres == nil || len(*node.Wheres) == 0 {
+			return "UPDATE without WHERE changes every row"
+		}
+	case *DeleteStatementNode:
+		if node.Wheres == nil || len(*node.Wheres) == 0 {
+			return "DELETE without WHERE removes every row"
+		}
+	}
+	return ""
+}
+
+func isNullValue(node Visitable) bool {
+	switch node := node.(type) {
+	case nil:
+		return true
+	case SqlLiteralNode:
+		return strings.EqualFold(node.Raw, "NULL")
+	case *SqlLiteralNode:
+		return node == nil || strings.EqualFold(node.Raw, "NULL")
+	case *ValueNode:
+		return node == nil || node.Value == nil
+	}
+	return false
+}
+
+func lintNotInNull(node Visitable, ctx LintContext) string {
+	if node, ok := node.(*NotInNode); ok {
+		for _, value := range node.Right {
+			if isNullValue(value) {
+				return "NOT IN with a NULL value never matches a row"
+			}
+		}
+	}
+	return ""
+}
+
+func hasLeadingWildcard(pattern Visitable) bool {
+	var raw string
+	switch pattern := pattern.(type) {
+	case *QuotedNode:
+		raw = pattern.Raw
+	case QuotedNode:
+		raw = pattern.Raw
+	case SqlLiteralNode:
+		raw = strings.TrimPrefix(pattern.Raw, "'")
+	case *SqlLiteralNode:
+		raw = strings.TrimPrefix(pattern.Raw, "'")
+	}
+	return strings.HasPrefix(raw, "%") || strings.HasPrefix(raw, "_")
+}
+
+func lintLeadingWildcard(node Visitable, ctx LintContext) string {
+	var pattern Visitable
+	switch node := node.(type) {
+	case *MatchesNode:
+		pattern = node.Right
+	case *DoesNotMatchNode:
+		pattern = node.Right
+	case *InfixOperationNode:
+		if operator := strings.ToUpper(node.Operator.Raw); operator == "LIKE" || operator == "ILIKE" {
+			pattern = node.Right
+		}
+	}
+	if pattern != nil && hasLeadingWildcard(pattern) {
+		return "LIKE pattern with a leading wildcard cannot use an index"
+	}
+	return ""
+}
+
+func hasOrders(node *SelectStatementNode) bool {
+	return node.Orders != nil && len(*node.Orders) > 0
+}
+
+func lintOffsetWithoutOrder(node Visitable, ctx LintContext) string {
+	if node, ok := node.(*SelectStatementNode); ok && node.Offset != nil && !hasOrders(node) {
+		return "OFFSET without ORDER BY skips arbitrary rows"
+	}
+	return ""
+}
+
+func lintSubqueryLimitWithoutOrder(node Visitable, ctx LintContext) string {
+	if node, ok := node.(*SelectStatementNode); ok && ctx.Subquery && node.Limit != nil && !hasOrders(node) {
+		return "LIMIT in a subquery without ORDER BY returns arbitrary rows"
+	}
+	return ""
+}
+
+func lintCartesianJoin(node Visitable, ctx LintContext) string {
+	switch node := node.(type) {
+	case *InnerJoinNode:
+		if node.Right == nil {
+			return "JOIN without ON produces a cartesian product"
+		}
+	case *OuterJoinNode:
+		if node.Right == nil {
+			return "JOIN without ON produces a cartesian product"
+		}
+	case *RightOuterJoinNode:
+		if node.Right == nil {
+			return "JOIN without ON produces a cartesian product"
+		}
+	case *FullOuterJoinNode:
+		if node.Right == nil {
+			return "JOIN without ON produces a cartesian product"
+		}
+	}
+	return ""
+}
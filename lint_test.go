@@ -0,0 +1,96 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint", func() {
+	users := NewTable("users")
+	posts := NewTable("posts")
+
+	rules := func(issues LintIssues) []string {
+		names := []string{}
+		for _, issue := range issues {
+			names = append(names, issue.Rule)
+		}
+		return names
+	}
+
+	It("reports nothing for a safe statement", func() {
+		mgr := users.Select(users.Attr("id")).Where(users.Attr("name").Matches(Sql("a%")))
+		mgr.Order(users.Attr("id").Asc()).Skip(10)
+		Expect(Lint(mgr)).To(BeEmpty())
+		Expect(Lint(mgr).Err()).NotTo(HaveOccurred())
+	})
+
+	It("reports updates and deletes without conditions as errors", func() {
+		update := NewUpdateManager(RelEngine).Table(users).Set(users.Attr("name"), Sql("x"))
+		issues := Lint(update)
+		Expect(rules(issues)).To(Equal([]string{"missing-where"}))
+		Expect(issues[0].Severity).To(Equal(LintSeverityError))
+		Expect(issues.Err()).To(MatchError(ContainSubstring("UPDATE without WHERE")))
+
+		Expect(rules(Lint(NewDeleteManager(RelEngine).From(users)))).To(Equal([]string{"missing-where"}))
+		Expect(Lint(NewDeleteManager(RelEngine).From(users).Where(users.Attr("id").Eq(Sql(1))))).To(BeEmpty())
+	})
+
+	It("reports NOT IN lists containing NULL", func() {
+		mgr := users.Where(users.Attr("id").NotIn([]Visitable{Sql(1), nil}))
+		Expect(rules(Lint(mgr))).To(Equal([]string{"not-in-null"}))
+		mgr = users.Where(users.Attr("id").NotIn([]Visitable{Sql(1), Sql("NULL")}))
+		Expect(rules(Lint(mgr))).To(Equal([]string{"not-in-null"}))
+	})
+
+	It("warns about LIKE patterns with a leading wildcard", func() {
+		mgr := users.Where(users.Attr("name").Matches(Sql("%a")))
+		issues := Lint(mgr)
+		Expect(rules(issues)).To(Equal([]string{"leading-wildcard"}))
+		Expect(issues[0].Severity).To(Equal(LintSeverityWarning))
+		Expect(issues.Err()).NotTo(HaveOccurred())
+
+		parsed, err := Parse("postgresql", `SELECT * FROM "users" WHERE "users"."name" LIKE '_a'`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rules(Lint(parsed))).To(Equal([]string{"leading-wildcard"}))
+	})
+
+	It("warns about offsets and subquery limits without an order", func() {
+		Expect(rules(Lint(users.Select(Star()).Skip(10)))).To(Equal([]string{"offset-without-order"}))
+		Expect(Lint(users.Select(Star()).Take(10))).To(BeEmpty())
+
+		recent := posts.Select(posts.Attr("user_id")).Take(10)
+		mgr := users.Where(users.Attr("id").In([]Visitable{recent}))
+		Expect(rules(Lint(mgr))).To(Equal([]string{"subquery-limit-without-order"}))
+		recent.Order(posts.Attr("id").Desc())
+		Expect(Lint(users.Where(users.Attr("id").In([]Visitable{recent})))).To(BeEmpty())
+	})
+
+	It("warns about joins without a condition", func() {
+		mgr := users.Select(Star()).Join(posts)
+		Expect(rules(Lint(mgr))).To(Equal([]string{"cartesian-join"}))
+		Expect(Lint(mgr.On(posts.Attr("user_id").Eq(users.Attr("id"))))).To(BeEmpty())
+	})
+
+	It("runs custom rules", func() {
+		noStar := LintRule{
+			Name:     "no-star",
+			Severity: LintSeverityError,
+			Check: func(node Visitable, ctx LintContext) string {
+				if core, ok := node.(*SelectCoreNode); ok && core.Selections != nil {
+					for _, selection := range *core.Selections {
+						if literal, ok := selection.(SqlLiteralNode); ok && literal.Raw == "*" {
+							return "select the needed columns"
+						}
+					}
+				}
+				return ""
+			},
+		}
+		mgr := users.Select(Star()).Skip(1)
+		Expect(rules(Lint(mgr, noStar))).To(Equal([]string{"no-star"}))
+		all := append(append([]LintRule{}, DefaultLintRules...), noStar)
+		Expect(rules(Lint(mgr, all...))).To(ConsistOf("no-star", "offset-without-order"))
+		Expect(Lint(mgr, all...).Err()).To(HaveOccurred())
+	})
+})
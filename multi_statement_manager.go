@@ -16,6 +16,11 @@ func NewMultiStatementManager(e Engine) *MultiStatementManager {
 	return &MultiStatementManager{Engine: e}
 }
 
+// As aliases the result of the set operation as a derived table
+func (mgr *MultiStatementManager) As(name string, columns ...string) *TableAliasNode {
+	return NewTableAliasNode(mgr.Ast, name, columns...)
+}
+
 func (mgr *MultiStatementManager) Intersect(stmt1 Visitable, stmt2 Visitable) *MultiStatementManager {
 	mgr.Ast = &IntersectNode{
 		Left:  stmt1,
@@ -47,3 +52,19 @@ func (mgr *MultiStatementManager) Except(stmt1 Visitable, stmt2 Visitable) *Mult
 	}
 	return mgr
 }
+
+// CountQuery returns a SelectManager counting the rows of the compound
+// statement by selecting from it as a derived table
+func (mgr *MultiStatementManager) CountQuery() *SelectManager {
+	counter := NewSelectManager(mgr.Engine, nil)
+	counter.Ctx.SetFrom(&TableAliasNode{
+		Relation: &GroupingNode{Expr: []Visitable{mgr.Ast}},
+		Name:     "subquery",
+	})
+	return counter.Select(&CountNode{Expressions: []Visitable{Star()}})
+}
+
+// Clone returns a deep copy, modifying it never affects mgr
+func (mgr *MultiStatementManager) Clone() *MultiStatementManager {
+	return Clone(mgr).(*MultiStatementManager)
+}
@@ -4,10 +4,17 @@ import (
 	"bytes"
 	"log"
 	"runtime/debug"
+	"strings"
 )
 
 type MysqlVisitor struct {
 	Conn Connector
+	// Expand ordered row value comparisons, which are not
+	// optimized before MySQL 8
+	LegacyRowValues bool
+	// Render constructs missing from the dialect as written instead of
+	// applying Emulations, use Validate to report them as errors
+	DisableEmulation bool
 }
 
 func (v MysqlVisitor) Accept(visitable Visitable) string {
@@ -15,6 +22,14 @@ func (v MysqlVisitor) Accept(visitable Visitable) string {
 }
 
 func (v MysqlVisitor) Visit(visitable Visitable) string {
+	if v.LegacyRowValues {
+		if expanded := expandRowValueComparison(visitable, true); expanded != nil {
+			return v.Visit(expanded)
+		}
+	}
+	if emulated := v.emulate(visitable); emulated != nil {
+		return v.Visit(emulated)
+	}
 	switch node := visitable.(type) {
 	case nil:
 		return visitationNil()
@@ -30,6 +45,12 @@ func (v MysqlVisitor) Visit(visitable Visitable) string {
 		return visitationJoinSourceNode(v, node)
 	case *EqualityNode:
 		return visitationEqualityNode(v, node)
+	case *RollupNode:
+		return v.visitRollupNode(node)
+	case *CubeNode:
+		return v.visitGroupingElement(node)
+	case *GroupingSetsNode:
+		return v.visitGroupingElement(node)
 	case *HavingNode:
 		return visitationHavingNode(v, node)
 	case *AttributeNode:
@@ -38,6 +59,10 @@ func (v MysqlVisitor) Visit(visitable Visitable) string {
 		return visitationGroupNode(v, node)
 	case *ExistsNode:
 		return visitationExistsNode(v, node)
+	case *AnyNode:
+		return visitationAnyNode(v, node)
+	case *AllNode:
+		return visitationAllNode(v, node)
 	case *AsNode:
 		return visitationAsNode(v, node)
 	case *LessThanNode:
@@ -61,13 +86,15 @@ func (v MysqlVisitor) Visit(visitable Visitable) string {
 	case *DescendingNode:
 		return visitationDescendingNode(v, node)
 	case *CountNode:
-		return visitationCountNode(v, node)
+		return v.visitCountNode(node)
 	case *AndNode:
 		return visitationAndNode(v, node)
 	case *TableAliasNode:
 		return visitationTableAliasNode(v, node)
 	case *InnerJoinNode:
 		return visitationInnerJoinNode(v, node)
+	case *TupleNode:
+		return visitationTupleNode(v, node)
 	case *GroupingNode:
 		return visitationGroupingNode(v, node)
 	case *NamedWindowNode:
@@ -78,6 +105,8 @@ func (v MysqlVisitor) Visit(visitable Visitable) string {
 		return visitationRowsNode(v, node)
 	case *LockNode:
 		return visitationLockNode(v, node)
+	case *RowLockNode:
+		return v.visitRowLockNode(node)
 	case *PrecedingNode:
 		return visitationPrecedingNode(v, node)
 	case *FollowingNode:
@@ -104,27 +133,45 @@ func (v MysqlVisitor) Visit(visitable Visitable) string {
 	case *InsertStatementNode:
 		return visitationInsertStatementNode(v, node)
 	case *SelectCoreNode:
-		return visitationSelectCoreNode(v, node)
+		return v.visitSelectCoreNode(node)
 	case *NotEqualNode:
 		return visitationNotEqualNode(v, node)
+	case *IsDistinctFromNode:
+		return v.visitIsDistinctFromNode(node)
+	case *IsNotDistinctFromNode:
+		return v.visitIsNotDistinctFromNode(node)
 	case *NotNode:
 		return visitationNotNode(v, node)
 	case *GreaterThanOrEqualNode:
 		return visitationGreaterThanOrEqualNode(v, node)
 	case *LessThanOrEqualNode:
 		return visitationLessThanOrEqualNode(v, node)
+	case *CaseNode:
+		return visitationCaseNode(v, node)
+	case *WhenNode:
+		return visitationWhenNode(v, node)
+	case *StringAggNode:
+		return v.visitStringAggNode(node)
+	case *ArrayAggNode:
+		return v.visitArrayAggNode(node)
+	case *JsonAggNode:
+		return v.visitJsonAggNode(node)
+	case *BoolAndNode:
+		return v.visitBoolAndNode(node)
+	case *BoolOrNode:
+		return v.visitBoolOrNode(node)
 	case *OrNode:
 		return visitationOrNode(v, node)
 	case *AvgNode:
-		return visitationAvgNode(v, node)
+		return v.visitAvgNode(node)
 	case *NamedFunctionNode:
-		return visitationNamedFunctionNode(v, node)
+		return v.visitNamedFunctionNode(node)
 	case *SumNode:
-		return visitationSumNode(v, node)
+		return v.visitSumNode(node)
 	case *MinNode:
-		return visitationMinNode(v, node)
+		return v.visitMinNode(node)
 	case *MaxNode:
-		return visitationMaxNode(v, node)
+		return v.visitMaxNode(node)
 	case *MatchesNode:
 		return visitationMatchesNode(v, node)
 	case *DoesNotMatchNode:
@@ -137,8 +184,14 @@ func (v MysqlVisitor) Visit(visitable Visitable) string {
 		return visitationExtractNode(v, node)
 	case *InfixOperationNode:
 		return visitationInfixOperationNode(v, node)
+	case *BindParamNode:
+		return visitationBindParamNode(v, node)
 	case *QuotedNode:
 		return visitationQuotedNode(v, node)
+	case *ValueNode:
+		return visitationValueNode(v, node)
+	case attributeColumn:
+		return visitationAttributeNode(v, node.attributeNode())
 	case *OverNode:
 		return visitationOverNode(v, node)
 	case *AssignmentNode:
@@ -149,6 +202,14 @@ func (v MysqlVisitor) Visit(visitable Visitable) string {
 		return visitationDistinctOnNode(v, node)
 	case *OuterJoinNode:
 		return visitationOuterJoinNode(v, node)
+	case *RightOuterJoinNode:
+		return visitationRightOuterJoinNode(v, node)
+	case *FullOuterJoinNode:
+		return visitationFullOuterJoinNode(v, node)
+	case *CommaJoinNode:
+		return visitationCommaJoinNode(v, node)
+	case *ValuesListNode:
+		return v.visitValuesListNode(node)
 	case *OffsetNode:
 		return visitationOffsetNode(v, node)
 	case *LimitNode:
@@ -180,10 +241,24 @@ func (v MysqlVisitor) QuoteTableName(visitable Visitable) string {
 			return alias.Name
 		}
 	}
+	if table, ok := visitable.(*Table); ok && table != nil {
+		return quoteQualifiedTableName(v.Conn, table)
+	}
 	return v.Conn.QuoteTableName(visitable.String())
 }
 
+// Quote escapes backslashes in strings as MySQL reads them as escapes
+// unless NO_BACKSLASH_ESCAPES is set, booleans are TRUE and FALSE
 func (v MysqlVisitor) Quote(thing interface{}) string {
+	switch t := thing.(type) {
+	case string:
+		return v.Conn.Quote(strings.Replace(t, `\`, `\\`, -1))
+	case bool:
+		if t {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
 	return v.Conn.Quote(thing)
 }
 
@@ -191,9 +266,170 @@ func (v MysqlVisitor) QuoteColumnName(literal SqlLiteralNode) string {
 	return v.Conn.QuoteColumnName(literal.Raw)
 }
 
+func (v MysqlVisitor) Capabilities() Capabilities {
+	return MysqlCapabilities
+}
+
+func (v MysqlVisitor) emulate(visitable Visitable) Visitable {
+	if v.DisableEmulation {
+		return nil
+	}
+	return emulate("mysql", visitable)
+}
+
 func (v MysqlVisitor) visitBinNode(node *BinNode) string {
 	var buf bytes.Buffer
 	buf.WriteString("BINARY ")
 	buf.WriteString(v.Visit(node.Expr))
 	return buf.String()
 }
+
+// MySQL uses the NULL-safe equal operator in place of IS NOT DISTINCT FROM
+func (v MysqlVisitor) visitIsDistinctFromNode(node *IsDistinctFromNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("NOT (")
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(" <=> ")
+	buf.WriteString(v.Visit(node.Right))
+	buf.WriteString(")")
+	return buf.String()
+}
+
+// MySQL writes the rows of a VALUES list as ROW constructors
+func (v MysqlVisitor) visitValuesListNode(node *ValuesListNode) string {
+	return visitationValuesListNode(v, node, "ROW")
+}
+
+func (v MysqlVisitor) visitIsNotDistinctFromNode(node *IsNotDistinctFromNode) string {
+	var buf bytes.Buffer
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(" <=> ")
+	buf.WriteString(v.Visit(node.Right))
+	return buf.String()
+}
+
+// MySQL supports UPDATE and SHARE locks, a plain SHARE lock is rendered
+// as LOCK IN SHARE MODE which is understood by every MySQL version.
+// FOR NO KEY UPDATE and FOR KEY SHARE are rendered as the stronger
+// FOR UPDATE and FOR SHARE, Validate still reports them
+func (v MysqlVisitor) visitRowLockNode(node *RowLockNode) string {
+	lock := *node
+	switch node.Strength {
+	case LockNoKeyUpdate:
+		lock.Strength = LockUpdate
+	case LockKeyShare:
+		lock.Strength = LockShare
+	}
+	if lock.Strength == LockShare && lock.Wait == LockWaitDefault && len(lock.Tables) == 0 {
+		return "LOCK IN SHARE MODE"
+	}
+	return visitationRowLockNode(v, &lock)
+}
+
+// MySQL only supports ROLLUP, written as a modifier after the
+// grouped expressions
+func (v MysqlVisitor) visitRollupNode(node *RollupNode) string {
+	var buf bytes.Buffer
+	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
+	buf.WriteString(" WITH ROLLUP")
+	return buf.String()
+}
+
+func (v MysqlVisitor) visitGroupingElement(node Visitable) string {
+	log.Fatalf("MysqlVisitor does not support %T", node)
+	return ""
+}
+
+func (v MysqlVisitor) visitSelectCoreNode(node *SelectCoreNode) string {
+	// WITH ROLLUP applies to every grouped expression so a rollup
+	// cannot be combined with other grouping elements
+	if node.Groups != nil && len(*node.Groups) > 1 {
+		for _, group := range *node.Groups {
+			if g, ok := group.(*GroupNode); ok {
+				if _, ok := g.Expr.(*RollupNode); ok {
+					log.Fatal("MysqlVisitor only supports a rollup as the sole grouping element")
+				}
+			}
+		}
+	}
+	return visitationSelectCoreNode(v, node)
+}
+
+// MySQL has no FILTER clause for aggregates, the condition is
+// moved into a CASE expression for each aggregated value
+func (v MysqlVisitor) visitAggregate(name string, node *FunctionNode) string {
+	return visitationAggregate(v, name, filterAsCase(node))
+}
+
+func (v MysqlVisitor) visitCountNode(node *CountNode) string {
+	return v.visitAggregate("COUNT", (*FunctionNode)(node))
+}
+
+func (v MysqlVisitor) visitSumNode(node *SumNode) string {
+	return v.visitAggregate("SUM", (*FunctionNode)(node))
+}
+
+func (v MysqlVisitor) visitAvgNode(node *AvgNode) string {
+	return v.visitAggregate("AVG", (*FunctionNode)(node))
+}
+
+func (v MysqlVisitor) visitMinNode(node *MinNode) string {
+	return v.visitAggregate("MIN", (*FunctionNode)(node))
+}
+
+func (v MysqlVisitor) visitMaxNode(node *MaxNode) string {
+	return v.visitAggregate("MAX", (*FunctionNode)(node))
+}
+
+func (v MysqlVisitor) visitNamedFunctionNode(node *NamedFunctionNode) string {
+	return v.visitAggregate(node.Name.Raw, &node.FunctionNode)
+}
+
+// only the aggregated value is moved into the CASE expression,
+// the separator is a constant
+func (v MysqlVisitor) visitStringAggNode(node *StringAggNode) string {
+	aggregated := *(*FunctionNode)(node)
+	aggregated.Expressions = node.Expressions[:1]
+	fn := filterAsCase(&aggregated)
+	var buf bytes.Buffer
+	buf.WriteString("GROUP_CONCAT(")
+	if fn.DistinctValues {
+		buf.WriteString("DISTINCT ")
+	}
+	buf.WriteString(v.Visit(fn.Expressions[0]))
+	if len(node.Expressions) > 1 {
+		buf.WriteString(" SEPARATOR ")
+		buf.WriteString(v.Visit(node.Expressions[1]))
+	}
+	buf.WriteString(")")
+	if fn.Alias != nil {
+		buf.WriteString(" AS ")
+		buf.WriteString(v.Visit(fn.Alias))
+	}
+	return buf.String()
+}
+
+func (v MysqlVisitor) visitArrayAggNode(node *ArrayAggNode) string {
+	return v.visitJsonArrayAgg((*FunctionNode)(node))
+}
+
+func (v MysqlVisitor) visitJsonAggNode(node *JsonAggNode) string {
+	return v.visitJsonArrayAgg((*FunctionNode)(node))
+}
+
+// JSON_ARRAYAGG keeps NULL values, so a FILTER cannot be moved
+// into a CASE expression like for the other aggregates
+func (v MysqlVisitor) visitJsonArrayAgg(node *FunctionNode) string {
+	if node.FilterExpr != nil {
+		log.Fatal("MysqlVisitor does not support FILTER on JSON_ARRAYAGG")
+	}
+	return visitationAggregate(v, "JSON_ARRAYAGG", node)
+}
+
+func (v MysqlVisitor) visitBoolAndNode(node *BoolAndNode) string {
+	return v.visitAggregate("MIN", (*FunctionNode)(node))
+}
+
+func (v MysqlVisitor) visitBoolOrNode(node *BoolOrNode) string {
+	return v.visitAggregate("MAX", (*FunctionNode)(node))
+}
@@ -0,0 +1,84 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MysqlVisitor", func() {
+	var visitor Visitor
+
+	BeforeEach(func() {
+		visitor = &MysqlVisitor{Conn: DefaultConnector{}}
+	})
+
+	It("uses the null-safe operator for is not distinct from", func() {
+		users := NewTable("users")
+		node := users.Attr("a").IsNotDistinctFrom(users.Attr("b"))
+		Expect(visitor.Accept(node)).To(Equal(`"users"."a" <=> "users"."b"`))
+	})
+
+	It("negates the null-safe operator for is distinct from", func() {
+		users := NewTable("users")
+		node := users.Attr("a").IsDistinctFrom(users.Attr("b"))
+		Expect(visitor.Accept(node)).To(Equal(`NOT ("users"."a" <=> "users"."b")`))
+		mgr := users.Select(Star()).Where(users.Attr("a").IsDistinctFrom(Sql(1))).Where(users.Attr("c").Eq(Sql(2)))
+		Expect(visitor.Accept(mgr.Ast)).To(HaveSuffix(`WHERE NOT ("users"."a" <=> 1) AND "users"."c" = 2`))
+	})
+
+	It("renders a plain share lock as LOCK IN SHARE MODE", func() {
+		stmt := NewSelectStatementNode()
+		stmt.Lock = NewLockNode(NewRowLockNode(LockShare))
+		Expect(visitor.Accept(stmt)).To(Equal(`SELECT LOCK IN SHARE MODE`))
+	})
+
+	It("renders share locks with a wait policy", func() {
+		stmt := NewSelectStatementNode()
+		stmt.Lock = NewLockNode(NewRowLockNode(LockShare).SkipLocked())
+		Expect(visitor.Accept(stmt)).To(Equal(`SELECT FOR SHARE SKIP LOCKED`))
+	})
+
+	It("renders the key lock modes MySQL does not have as stronger locks", func() {
+		stmt := NewSelectStatementNode()
+		stmt.Lock = NewLockNode(NewRowLockNode(LockKeyShare))
+		Expect(visitor.Accept(stmt)).To(Equal(`SELECT LOCK IN SHARE MODE`))
+		Expect(Validate(stmt, "mysql")).To(MatchError(ContainSubstring("FOR KEY SHARE is not supported")))
+		stmt.Lock = NewLockNode(NewRowLockNode(LockNoKeyUpdate).NoWait())
+		Expect(visitor.Accept(stmt)).To(Equal(`SELECT FOR UPDATE NOWAIT`))
+	})
+
+	It("renders a rollup as WITH ROLLUP", func() {
+		sales := NewTable("sales")
+		core := NewSelectCoreNode()
+		core.Groups = &[]Visitable{NewGroupNode(Rollup(sales.Attr("year"), sales.Attr("month")))}
+		Expect(visitor.Accept(core)).To(Equal(`SELECT GROUP BY "sales"."year", "sales"."month" WITH ROLLUP`))
+	})
+
+	It("moves an aggregate filter into a case expression", func() {
+		users := NewTable("users")
+		node := Sum(users.Attr("total")).Filter(users.Attr("paid").Eq(&TrueNode{}))
+		Expect(visitor.Accept(node)).To(Equal(`SUM(CASE WHEN "users"."paid" = TRUE THEN "users"."total" END)`))
+	})
+
+	It("counts a constant for a filtered COUNT(*)", func() {
+		node := Star().Count().Filter(Sql("paid"))
+		Expect(visitor.Accept(node)).To(Equal(`COUNT(CASE WHEN paid THEN 1 END)`))
+	})
+
+	It("renders a string aggregate as GROUP_CONCAT", func() {
+		users := NewTable("users")
+		node := StringAgg(users.Attr("name"), &QuotedNode{Raw: ","}).Distinct()
+		Expect(visitor.Accept(node)).To(Equal(`GROUP_CONCAT(DISTINCT "users"."name" SEPARATOR ',')`))
+		node = StringAgg(users.Attr("name"), &QuotedNode{Raw: ";"}).Filter(users.Attr("active").Eq(Sql(true)))
+		Expect(visitor.Accept(node)).To(Equal(`GROUP_CONCAT(CASE WHEN "users"."active" = true THEN "users"."name" END SEPARATOR ';')`))
+	})
+
+	It("renders JSON aggregates as JSON_ARRAYAGG without filters", func() {
+		users := NewTable("users")
+		Expect(visitor.Accept(ArrayAgg(users.Attr("id")).Distinct())).To(Equal(`JSON_ARRAYAGG(DISTINCT "users"."id")`))
+		filtered := JsonAgg(users.Attr("id")).Filter(users.Attr("active").Eq(Sql(true)))
+		Expect(Validate(filtered, "mysql")).To(MatchError(ContainSubstring("FILTER on JSON aggregates is not supported")))
+		Expect(Validate(filtered, "postgresql")).To(Succeed())
+	})
+})
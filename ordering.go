@@ -6,6 +6,42 @@ type Orderer interface {
 	Visitable
 }
 
+// NullsOrder places NULL values before or after the other values,
+// the default is left to the database
+type NullsOrder int
+
+const (
+	NullsDefault NullsOrder = iota
+	NullsFirst
+	NullsLast
+)
+
+func (n NullsOrder) String() string {
+	switch n {
+	case NullsFirst:
+		return "NULLS FIRST"
+	case NullsLast:
+		return "NULLS LAST"
+	}
+	return ""
+}
+
+func (n NullsOrder) reverse() NullsOrder {
+	switch n {
+	case NullsFirst:
+		return NullsLast
+	case NullsLast:
+		return NullsFirst
+	}
+	return NullsDefault
+}
+
+type OrderingNode struct {
+	Expr  Visitable
+	Nulls NullsOrder
+	BaseVisitable
+}
+
 func orderingDesc(node Orderer) *DescendingNode {
 	return &DescendingNode{Expr: node}
 }
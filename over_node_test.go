@@ -34,4 +34,13 @@ var _ = Describe("AscendingNode", func() {
 		expected := "SELECT COUNT(\"users\".\"id\") OVER (ORDER BY \"users\".\"foo\") FROM \"users\""
 		Expect(sql).To(Equal(expected))
 	})
+	It("should partition the window", func() {
+		users := NewTable("users")
+		mgr := users.From(users)
+		window := (&WindowNode{}).Partition(users.Attr("team_id")).Order(users.Attr("foo"))
+		mgr.Project(users.Attr("id").Count().Over(window))
+		sql := mgr.ToSql()
+		expected := "SELECT COUNT(\"users\".\"id\") OVER (PARTITION BY \"users\".\"team_id\" ORDER BY \"users\".\"foo\") FROM \"users\""
+		Expect(sql).To(Equal(expected))
+	})
 })
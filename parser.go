@@ -0,0 +1,1330 @@
+package rel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseError reports SQL that could not be parsed, Pos is
+// the byte offset of the offending token in the input
+type ParseError struct {
+	Pos     int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("rel: %s at position %d", e.Message, e.Pos)
+}
+
+// Parse turns a single SQL statement into the manager that would build it,
+// a *SelectManager, *MultiStatementManager, *InsertManager, *UpdateManager
+// or *DeleteManager using the engine of the dialect. The dialect is one of
+// the names accepted by RegisterDatabase. Like MySQL itself, the mysql
+// dialect reads double quoted text as a string, names use backticks.
+func Parse(dialect string, sql string) (mgr Visitable, err error) {
+	engine := databaseEngine(dialect)
+	if engine == nil {
+		return nil, fmt.Errorf("rel: unknown dialect %q", dialect)
+	}
+	tokens, err := lex(dialect, sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, dialect: dialect, engine: engine}
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(*ParseError)
+			if !ok {
+				panic(r)
+			}
+			mgr, err = nil, perr
+		}
+	}()
+	mgr = p.parseStatement()
+	p.accept(";")
+	if p.peek().kind != tokenEOF {
+		p.fail("unexpected %s", p.peek())
+	}
+	return mgr, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenParam
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string // identifiers are unquoted, strings keep their escapes
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// symbols are matched longest first
+var lexSymbols = []string{"<=>", "<>", "!=", "<=", ">=", "||", "(", ")", ",", ".", ";", "*", "+", "-", "/", "%", "=", "<", ">"}
+
+func lex(dialect string, sql string) ([]token, error) {
+	tokens := []token{}
+	i := 0
+	for i < len(sql) {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(sql[i:], "--"):
+			for i < len(sql) && sql[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil, &ParseError{i, "unterminated comment"}
+			}
+			i += end + 4
+		case c == '\'':
+			start := i
+			i++
+			for {
+				if i >= len(sql) {
+					return nil, &ParseError{start, "unterminated string"}
+				}
+				// MySQL reads backslashes in strings as escapes
+				if sql[i] == '\\' && dialect == "mysql" {
+					i += 2
+					continue
+				}
+				if sql[i] == '\'' {
+					if i+1 < len(sql) && sql[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, token{tokenString, sql[start+1 : i], start})
+			i++
+		case c == '"' && dialect == "mysql":
+			// MySQL reads double quoted text as a string
+			start := i
+			var text bytes.Buffer
+			i++
+			for {
+				if i >= len(sql) {
+					return nil, &ParseError{start, "unterminated string"}
+				}
+				if sql[i] == '\\' {
+					if i+1 < len(sql) {
+						text.WriteByte(sql[i])
+						text.WriteByte(sql[i+1])
+					}
+					i += 2
+					continue
+				}
+				if sql[i] == '"' {
+					if i+1 < len(sql) && sql[i+1] == '"' {
+						text.WriteByte('"')
+						i += 2
+						continue
+					}
+					break
+				}
+				// the string is rendered between single quotes
+				if sql[i] == '\'' {
+					text.WriteByte('\'')
+				}
+				text.WriteByte(sql[i])
+				i++
+			}
+			tokens = append(tokens, token{tokenString, text.String(), start})
+			i++
+		case c == '"' || (c == '`' && dialect != "postgresql"):
+			// a doubled quote is a quote in the name
+			start := i
+			var name bytes.Buffer
+			i++
+			for {
+				if i >= len(sql) {
+					return nil, &ParseError{start, "unterminated identifier"}
+				}
+				if sql[i] == c {
+					if i+1 < len(sql) && sql[i+1] == c {
+						name.WriteByte(c)
+						i += 2
+						continue
+					}
+					break
+				}
+				name.WriteByte(sql[i])
+				i++
+			}
+			tokens = append(tokens, token{tokenQuotedIdent, name.String(), start})
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(sql) && (isDigit(sql[i]) || sql[i] == '.') {
+				i++
+			}
+			// an exponent needs digits, 1e is a number and its alias
+			if i < len(sql) && (sql[i] == 'e' || sql[i] == 'E') {
+				j := i + 1
+				if j < len(sql) && (sql[j] == '+' || sql[j] == '-') {
+					j++
+				}
+				if j < len(sql) && isDigit(sql[j]) {
+					for i = j; i < len(sql) && isDigit(sql[i]); i++ {
+					}
+				}
+			}
+			tokens = append(tokens, token{tokenNumber, sql[start:i], start})
+		case c == '?' || (c == '$' && i+1 < len(sql) && isDigit(sql[i+1])) || (c == ':' && i+1 < len(sql) && isWordByte(sql[i+1])):
+			start := i
+			i++
+			for i < len(sql) && isWordByte(sql[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenParam, sql[start:i], start})
+		case isWordByte(c):
+			start := i
+			for i < len(sql) && (isWordByte(sql[i]) || isDigit(sql[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, sql[start:i], start})
+		default:
+			matched := false
+			for _, symbol := range lexSymbols {
+				if strings.HasPrefix(sql[i:], symbol) {
+					tokens = append(tokens, token{tokenSymbol, symbol, i})
+					i += len(symbol)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, &ParseError{i, fmt.Sprintf("unexpected character %q", c)}
+			}
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(sql)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80 || isDigit(c)
+}
+
+// words which end an expression and cannot be used as a bare alias
+var reservedWords = map[string]bool{
+	"ALL": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true,
+	"CASE": true, "CROSS": true, "DESC": true, "DISTINCT": true, "ELSE": true,
+	"END": true, "EXCEPT": true, "EXISTS": true, "FILTER": true, "FOR": true,
+	"FROM": true, "FULL": true, "GROUP": true, "HAVING": true, "IN": true,
+	"INNER": true, "INTERSECT": true, "IS": true, "JOIN": true, "LEFT": true,
+	"LIKE": true, "ILIKE": true, "LIMIT": true, "NOT": true, "NULL": true, "OFFSET": true,
+	"ON": true, "OR": true, "ORDER": true, "OUTER": true, "OVER": true,
+	"RIGHT": true, "SELECT": true, "SET": true, "THEN": true, "UNION": true,
+	"USING": true, "VALUES": true, "WHEN": true, "WHERE": true, "WINDOW": true,
+	"WITH": true, "LOCK": true, "NATURAL": true, "NULLS": true, "RETURNING": true,
+	"SEPARATOR": true,
+}
+
+// keywords which read like columns but are functions without parentheses
+var niladicFunctions = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"CURRENT_USER": true, "LOCALTIME": true, "LOCALTIMESTAMP": true,
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	dialect string
+	engine  Engine
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	panic(&ParseError{p.peek().pos, fmt.Sprintf(format, args...)})
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) peekAt(offset int) token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is reports whether the next token is the keyword or symbol
+func (p *parser) is(text string) bool {
+	return p.isAt(0, text)
+}
+
+func (p *parser) isAt(offset int, text string) bool {
+	t := p.peekAt(offset)
+	switch t.kind {
+	case tokenWord:
+		return strings.EqualFold(t.text, text)
+	case tokenSymbol:
+		return t.text == text
+	}
+	return false
+}
+
+func (p *parser) accept(texts ...string) bool {
+	for i, text := range texts {
+		if !p.isAt(i, text) {
+			return false
+		}
+	}
+	p.pos += len(texts)
+	return true
+}
+
+func (p *parser) expect(texts ...string) {
+	for _, text := range texts {
+		if !p.accept(text) {
+			p.fail("expected %s but found %s", text, p.peek())
+		}
+	}
+}
+
+func (p *parser) isIdentifier() bool {
+	t := p.peek()
+	return t.kind == tokenQuotedIdent || (t.kind == tokenWord && !reservedWords[strings.ToUpper(t.text)])
+}
+
+func (p *parser) identifier() string {
+	if !p.isIdentifier() {
+		p.fail("expected an identifier but found %s", p.peek())
+	}
+	return p.next().text
+}
+
+func (p *parser) table(name string) *Table {
+	table := NewTable(name)
+	table.Engine = p.engine
+	return table
+}
+
+// qualifiedTable reads [[database.]schema.]name
+func (p *parser) qualifiedTable(names []string) *Table {
+	if len(names) > 3 {
+		p.fail("too many qualifiers in %s", strings.Join(names, "."))
+	}
+	table := p.table(names[len(names)-1])
+	if len(names) > 1 {
+		table.Schema = names[len(names)-2]
+	}
+	if len(names) > 2 {
+		table.Database = names[0]
+	}
+	return table
+}
+
+func (p *parser) parseStatement() Visitable {
+	switch {
+	case p.is("SELECT"), p.is("WITH"), p.is("("):
+		return p.parseQuery()
+	case p.is("INSERT"):
+		return p.parseInsert()
+	case p.is("UPDATE"):
+		return p.parseUpdate()
+	case p.is("DELETE"):
+		return p.parseDelete()
+	}
+	p.fail("expected a statement but found %s", p.peek())
+	return nil
+}
+
+func (p *parser) parseQuery() Visitable {
+	node := p.parseQueryExpression()
+	if stmt, ok := node.(*SelectStatementNode); ok {
+		return &SelectManager{Engine: p.engine, Ast: stmt, Ctx: stmt.Cores[len(stmt.Cores)-1]}
+	}
+	return &MultiStatementManager{Engine: p.engine, Ast: node}
+}
+
+// parseQueryExpression parses a select statement or set operation
+// with its optional WITH, ORDER BY, LIMIT, OFFSET and locking clauses
+func (p *parser) parseQueryExpression() Visitable {
+	var with Visitable
+	if p.accept("WITH") {
+		recursive := p.accept("RECURSIVE")
+		with = p.parseCommonTableExpression()
+		if p.is(",") {
+			p.fail("only a single common table expression is supported")
+		}
+		if recursive {
+			with = &WithRecursiveNode{Expr: with}
+		} else {
+			with = &WithNode{Expr: with}
+		}
+	}
+
+	node := p.parseSetOperation()
+	stmt, simple := node.(*SelectStatementNode)
+	if !simple {
+		if p.is("FOR") || p.is("LOCK") {
+			p.fail("locking a compound query is not supported")
+		}
+		if with == nil && !p.is("ORDER") && !p.is("LIMIT") && !p.is("OFFSET") {
+			return node
+		}
+		// the clauses apply to the compound query selected as a
+		// derived table, ORDER BY can only name its columns
+		stmt = NewSelectStatementNode()
+		stmt.Cores[0].SetFrom(&TableAliasNode{Relation: &GroupingNode{Expr: []Visitable{node}}, Name: "rel_compound", Quoted: true})
+		stmt.Cores[0].Selections = &[]Visitable{Star()}
+	}
+
+	stmt.With = with
+	p.parseOrderLimitLock(stmt)
+	return stmt
+}
+
+func (p *parser) parseCommonTableExpression() Visitable {
+	name := p.identifier()
+	if p.is("(") {
+		p.fail("column lists on common table expressions are not supported")
+	}
+	p.expect("AS", "(")
+	body := p.parseQueryExpression()
+	p.expect(")")
+	if _, ok := body.(*SelectStatementNode); ok {
+		body = &GroupingNode{Expr: []Visitable{body}}
+	}
+	return &AsNode{Left: p.table(name), Right: body}
+}
+
+func (p *parser) parseOrderLimitLock(stmt *SelectStatementNode) {
+	if p.accept("ORDER", "BY") {
+		orders := p.parseOrders()
+		stmt.Orders = &orders
+	}
+	if p.accept("LIMIT") {
+		limit := p.parseExpression()
+		if p.accept(",") {
+			stmt.Offset = NewOffsetNode(limit)
+			limit = p.parseExpression()
+		}
+		stmt.Limit = NewLimitNode(limit)
+	}
+	if p.accept("OFFSET") {
+		stmt.Offset = NewOffsetNode(p.parseExpression())
+		p.accept("ROWS")
+	}
+	if p.is("FOR") || p.is("LOCK") {
+		stmt.Lock = NewLockNode(p.parseRowLock())
+	}
+}
+
+func (p *parser) parseRowLock() Visitable {
+	if p.accept("LOCK", "IN", "SHARE", "MODE") {
+		return NewRowLockNode(LockShare)
+	}
+	p.expect("FOR")
+	var lock *RowLockNode
+	switch {
+	case p.accept("UPDATE"):
+		lock = NewRowLockNode(LockUpdate)
+	case p.accept("NO", "KEY", "UPDATE"):
+		lock = NewRowLockNode(LockNoKeyUpdate)
+	case p.accept("SHARE"):
+		lock = NewRowLockNode(LockShare)
+	case p.accept("KEY", "SHARE"):
+		lock = NewRowLockNode(LockKeyShare)
+	default:
+		p.fail("expected a lock strength but found %s", p.peek())
+	}
+	if p.accept("OF") {
+		for {
+			lock.Of(p.parseTableName(p.identifier()))
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("NOWAIT") {
+		lock.NoWait()
+	} else if p.accept("SKIP", "LOCKED") {
+		lock.SkipLocked()
+	}
+	return lock
+}
+
+// UNION and EXCEPT bind looser than INTERSECT
+func (p *parser) parseSetOperation() Visitable {
+	left := p.parseIntersection()
+	for {
+		switch {
+		case p.accept("UNION", "ALL"):
+			left = &UnionAllNode{Left: left, Right: p.parseIntersection()}
+		case p.accept("UNION"):
+			p.accept("DISTINCT")
+			left = &UnionNode{Left: left, Right: p.parseIntersection()}
+		case p.accept("EXCEPT"):
+			left = &ExceptNode{Left: left, Right: p.parseIntersection()}
+		default:
+			return left
+		}
+	}
+}
+
+func (p *parser) parseIntersection() Visitable {
+	left := p.parseSetTerm()
+	for p.accept("INTERSECT") {
+		left = &IntersectNode{Left: left, Right: p.parseSetTerm()}
+	}
+	return left
+}
+
+func (p *parser) parseSetTerm() Visitable {
+	if p.accept("(") {
+		node := p.parseQueryExpression()
+		p.expect(")")
+		return node
+	}
+	stmt := &SelectStatementNode{Cores: []*SelectCoreNode{p.parseSelectCore()}}
+	return stmt
+}
+
+func (p *parser) parseSelectCore() *SelectCoreNode {
+	p.expect("SELECT")
+	core := NewSelectCoreNode()
+
+	if p.accept("DISTINCT") {
+		if p.accept("ON") {
+			p.expect("(")
+			exprs := p.parseExpressions()
+			p.expect(")")
+			if len(exprs) == 1 {
+				core.SetQuantifier = NewDistinctOnNode(exprs[0])
+			} else {
+				core.SetQuantifier = NewDistinctOnNode(Tuple(exprs...))
+			}
+		} else {
+			core.SetQuantifier = &DistinctNode{}
+		}
+	} else {
+		p.accept("ALL")
+	}
+
+	if !p.is("FROM") {
+		selections := []Visitable{}
+		for {
+			selections = append(selections, p.parseProjection())
+			if !p.accept(",") {
+				break
+			}
+		}
+		core.Selections = &selections
+	}
+
+	if p.accept("FROM") {
+		core.Source.Left = p.parseTableReference()
+		core.Source.Right = p.parseJoins()
+	}
+
+	if p.accept("WHERE") {
+		core.Wheres = &[]Visitable{p.parseExpression()}
+	}
+
+	if p.accept("GROUP", "BY") {
+		groups := []Visitable{}
+		for {
+			groups = append(groups, p.parseGroupingElement())
+			if !p.accept(",") {
+				break
+			}
+		}
+		if p.accept("WITH", "ROLLUP") {
+			exprs := make([]Visitable, len(groups))
+			for i, group := range groups {
+				exprs[i] = group.(*GroupNode).Expr
+			}
+			groups = []Visitable{NewGroupNode(Rollup(exprs...))}
+		}
+		core.Groups = &groups
+	}
+
+	if p.accept("HAVING") {
+		core.Having = NewHavingNode(p.parseExpression())
+	}
+
+	if p.accept("WINDOW") {
+		windows := []Visitable{}
+		for {
+			window := &NamedWindowNode{Name: Sql(p.identifier())}
+			p.expect("AS", "(")
+			window.Partitions, window.Orders, window.Framing = p.parseWindowDefinition()
+			p.expect(")")
+			windows = append(windows, window)
+			if !p.accept(",") {
+				break
+			}
+		}
+		core.Windows = &windows
+	}
+
+	resolveAliases(core)
+	return core
+}
+
+// resolveAliases points attributes qualified with a table alias
+// at the aliased table, like attributes built from that table
+func resolveAliases(core *SelectCoreNode) {
+	aliases := map[string]Visitable{}
+	sources := append([]Visitable{core.Source.Left}, core.Source.Right...)
+	for _, source := range sources {
+		switch s := source.(type) {
+		case *InnerJoinNode:
+			source = s.Left
+		case *OuterJoinNode:
+			source = s.Left
+		case *RightOuterJoinNode:
+			source = s.Left
+		case *FullOuterJoinNode:
+			source = s.Left
+		case *CommaJoinNode:
+			source = s.Left
+		}
+		switch s := source.(type) {
+		case *Table:
+			if s.TableAlias != "" {
+				aliases[s.TableAlias] = s
+			}
+		case *TableAliasNode:
+			aliases[s.Name] = s
+		}
+	}
+	if len(aliases) == 0 {
+		return
+	}
+	Walk(core, func(node Visitable) bool {
+		if attr, ok := node.(*AttributeNode); ok {
+			if t, ok := attr.Relation.(*Table); ok && t.TableAlias == "" && t.Schema == "" {
+				if aliased, ok := aliases[t.Name]; ok {
+					attr.Relation = aliased
+				}
+			}
+		}
+		return true
+	})
+}
+
+func (p *parser) parseProjection() Visitable {
+	if p.accept("*") {
+		return Star()
+	}
+	expr := p.parseExpression()
+	if p.accept("AS") || p.isIdentifier() {
+		return &AsNode{Left: expr, Right: Sql(p.aliasName())}
+	}
+	return expr
+}
+
+// aliasName keeps the quotes of a quoted alias
+func (p *parser) aliasName() string {
+	t := p.peek()
+	name := p.identifier()
+	if t.kind == tokenQuotedIdent {
+		return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+	}
+	return name
+}
+
+func (p *parser) parseTableReference() Visitable {
+	if p.accept("(") {
+		var relation Visitable
+		if p.accept("VALUES") {
+			relation = p.parseValuesList()
+		} else {
+			relation = p.parseQueryExpression()
+		}
+		p.expect(")")
+		p.accept("AS")
+		return p.parseAlias(&GroupingNode{Expr: []Visitable{relation}})
+	}
+	name := p.identifier()
+	if p.accept("(") {
+		function := TableFunction(name)
+		if !p.is(")") {
+			function.Expressions = p.parseExpressions()
+		}
+		p.expect(")")
+		if p.accept("AS") || p.isIdentifier() {
+			return p.parseAlias(function)
+		}
+		return function
+	}
+	table := p.parseTableName(name)
+	if p.accept("AS") || p.isIdentifier() {
+		table.TableAlias = p.identifier()
+	}
+	return table
+}
+
+// parseTableName reads the qualifiers following the first name
+func (p *parser) parseTableName(name string) *Table {
+	names := []string{name}
+	for p.accept(".") {
+		names = append(names, p.identifier())
+	}
+	return p.qualifiedTable(names)
+}
+
+// parseAlias reads the alias of a relation with its optional column list
+func (p *parser) parseAlias(relation Visitable) *TableAliasNode {
+	t := p.peek()
+	alias := NewTableAliasNode(relation, p.identifier())
+	alias.Quoted = t.kind == tokenQuotedIdent
+	if p.accept("(") {
+		for {
+			alias.Columns = append(alias.Columns, p.identifier())
+			if !p.accept(",") {
+				break
+			}
+		}
+		p.expect(")")
+	}
+	return alias
+}
+
+func (p *parser) parseValuesList() *ValuesListNode {
+	values := NewValuesListNode()
+	for {
+		p.accept("ROW")
+		p.expect("(")
+		row := []interface{}{}
+		for _, expr := range p.parseExpressions() {
+			row = append(row, expr)
+		}
+		p.expect(")")
+		values.Row(row...)
+		if !p.accept(",") {
+			return values
+		}
+	}
+}
+
+func (p *parser) parseJoins() []Visitable {
+	joins := []Visitable{}
+	for {
+		switch {
+		case p.accept("JOIN"), p.accept("INNER", "JOIN"):
+			join := &InnerJoinNode{Left: p.parseTableReference()}
+			join.Right = p.parseJoinConstraint()
+			joins = append(joins, join)
+		case p.accept("LEFT", "JOIN"), p.accept("LEFT", "OUTER", "JOIN"):
+			join := &OuterJoinNode{Left: p.parseTableReference()}
+			join.Right = p.parseJoinConstraint()
+			joins = append(joins, join)
+		case p.accept("RIGHT", "JOIN"), p.accept("RIGHT", "OUTER", "JOIN"):
+			join := &RightOuterJoinNode{Left: p.parseTableReference()}
+			join.Right = p.parseJoinConstraint()
+			joins = append(joins, join)
+		case p.accept("FULL", "JOIN"), p.accept("FULL", "OUTER", "JOIN"):
+			join := &FullOuterJoinNode{Left: p.parseTableReference()}
+			join.Right = p.parseJoinConstraint()
+			joins = append(joins, join)
+		case p.accept(","):
+			joins = append(joins, &CommaJoinNode{Left: p.parseTableReference()})
+		case p.is("CROSS"), p.is("NATURAL"):
+			p.fail("%s joins are not supported", strings.ToUpper(p.peek().text))
+		default:
+			return joins
+		}
+	}
+}
+
+func (p *parser) parseJoinConstraint() Visitable {
+	if p.accept("ON") {
+		return &OnNode{Expr: p.parseExpression()}
+	}
+	if p.accept("USING") {
+		p.expect("(")
+		column := p.identifier()
+		p.expect(")")
+		return &UsingNode{Expr: &QuotedNode{Raw: column}}
+	}
+	return nil
+}
+
+func (p *parser) parseGroupingElement() Visitable {
+	switch {
+	case p.accept("ROLLUP"):
+		p.expect("(")
+		exprs := p.parseExpressions()
+		p.expect(")")
+		return NewGroupNode(Rollup(exprs...))
+	case p.accept("CUBE"):
+		p.expect("(")
+		exprs := p.parseExpressions()
+		p.expect(")")
+		return NewGroupNode(Cube(exprs...))
+	case p.accept("GROUPING", "SETS"):
+		p.expect("(")
+		exprs := p.parseExpressions()
+		p.expect(")")
+		return NewGroupNode(GroupingSets(exprs...))
+	}
+	return NewGroupNode(p.parseExpression())
+}
+
+func (p *parser) parseOrders() []Visitable {
+	orders := []Visitable{}
+	for {
+		expr := p.parseExpression()
+		if p.accept("DESC") {
+			expr = &DescendingNode{Expr: expr}
+		} else if p.accept("ASC") {
+			expr = &AscendingNode{Expr: expr}
+		}
+		if p.is("NULLS") {
+			expr = p.parseNullsOrder(expr)
+		}
+		orders = append(orders, expr)
+		if !p.accept(",") {
+			return orders
+		}
+	}
+}
+
+func (p *parser) parseNullsOrder(expr Visitable) Visitable {
+	nulls := NullsLast
+	if p.accept("NULLS", "FIRST") {
+		nulls = NullsFirst
+	} else {
+		p.expect("NULLS", "LAST")
+	}
+	switch order := expr.(type) {
+	case *AscendingNode:
+		order.Nulls = nulls
+	case *DescendingNode:
+		order.Nulls = nulls
+	default:
+		return &AscendingNode{Expr: expr, Nulls: nulls}
+	}
+	return expr
+}
+
+func (p *parser) parseWindowDefinition() (partitions *[]Visitable, orders *[]Visitable, framing Visitable) {
+	if p.accept("PARTITION", "BY") {
+		exprs := p.parseExpressions()
+		partitions = &exprs
+	}
+	if p.accept("ORDER", "BY") {
+		exprs := p.parseOrders()
+		orders = &exprs
+	}
+	if p.is("ROWS") || p.is("RANGE") {
+		rows := p.next()
+		var frame Visitable
+		if p.accept("BETWEEN") {
+			start := p.parseFrameBound()
+			p.expect("AND")
+			frame = &BetweenNode{Right: &AndNode{Children: &[]Visitable{start, p.parseFrameBound()}}}
+		} else {
+			frame = p.parseFrameBound()
+		}
+		if between, ok := frame.(*BetweenNode); ok {
+			if strings.EqualFold(rows.text, "ROWS") {
+				between.Left = &RowsNode{}
+			} else {
+				between.Left = &RangeNode{}
+			}
+			framing = between
+		} else if strings.EqualFold(rows.text, "ROWS") {
+			framing = &RowsNode{Expr: frame}
+		} else {
+			framing = &RangeNode{Expr: frame}
+		}
+	}
+	return partitions, orders, framing
+}
+
+func (p *parser) parseFrameBound() Visitable {
+	if p.accept("CURRENT", "ROW") {
+		return &CurrentRowNode{}
+	}
+	var expr Visitable
+	if !p.accept("UNBOUNDED") {
+		expr = p.parseAdditive()
+	}
+	if p.accept("PRECEDING") {
+		return &PrecedingNode{Expr: expr}
+	}
+	p.expect("FOLLOWING")
+	return &FollowingNode{Expr: expr}
+}
+
+func (p *parser) parseExpressions() []Visitable {
+	exprs := []Visitable{}
+	for {
+		exprs = append(exprs, p.parseExpression())
+		if !p.accept(",") {
+			return exprs
+		}
+	}
+}
+
+func (p *parser) parseExpression() Visitable {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() Visitable {
+	left := p.parseAnd()
+	for p.accept("OR") || (p.dialect == "mysql" && p.accept("||")) {
+		left = &OrNode{Left: left, Right: p.parseAnd()}
+	}
+	return left
+}
+
+func (p *parser) parseAnd() Visitable {
+	children := []Visitable{p.parseNot()}
+	for p.accept("AND") {
+		children = append(children, p.parseNot())
+	}
+	if len(children) == 1 {
+		return children[0]
+	}
+	return &AndNode{Children: &children}
+}
+
+func (p *parser) parseNot() Visitable {
+	if p.accept("NOT") {
+		return NewNotNode(p.parseNot())
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() Visitable {
+	left := p.parseAdditive()
+	switch {
+	case p.accept("="):
+		return &EqualityNode{Left: left, Right: p.parseComparand()}
+	case p.accept("!="), p.accept("<>"):
+		return &NotEqualNode{Left: left, Right: p.parseComparand()}
+	case p.accept("<"):
+		return &LessThanNode{Left: left, Right: p.parseComparand()}
+	case p.accept("<="):
+		return &LessThanOrEqualNode{Left: left, Right: p.parseComparand()}
+	case p.accept(">"):
+		return &GreaterThanNode{Left: left, Right: p.parseComparand()}
+	case p.accept(">="):
+		return &GreaterThanOrEqualNode{Left: left, Right: p.parseComparand()}
+	case p.accept("<=>"):
+		return &IsNotDistinctFromNode{Left: left, Right: p.parseAdditive()}
+	case p.accept("IS", "NOT", "NULL"):
+		return &NotEqualNode{Left: left}
+	case p.accept("IS", "NULL"):
+		return &EqualityNode{Left: left}
+	case p.accept("IS", "NOT", "DISTINCT", "FROM"):
+		return &IsNotDistinctFromNode{Left: left, Right: p.parseAdditive()}
+	case p.accept("IS", "DISTINCT", "FROM"):
+		return &IsDistinctFromNode{Left: left, Right: p.parseAdditive()}
+	case p.accept("IN"):
+		return &InNode{Left: left, Right: p.parseInList()}
+	case p.accept("NOT", "IN"):
+		return &NotInNode{Left: left, Right: p.parseInList()}
+	case p.dialect == "postgresql" && p.accept("ILIKE"):
+		return &MatchesNode{Left: left, Right: p.parseAdditive()}
+	case p.dialect == "postgresql" && p.accept("NOT", "ILIKE"):
+		return &DoesNotMatchNode{Left: left, Right: p.parseAdditive()}
+	case p.dialect == "postgresql" && p.accept("LIKE"):
+		// matches are case insensitive on PostgreSQL, LIKE is kept as written
+		return &InfixOperationNode{Operator: Sql("LIKE"), Left: left, Right: p.parseAdditive()}
+	case p.dialect == "postgresql" && p.accept("NOT", "LIKE"):
+		return &InfixOperationNode{Operator: Sql("NOT LIKE"), Left: left, Right: p.parseAdditive()}
+	case p.accept("LIKE"):
+		return &MatchesNode{Left: left, Right: p.parseAdditive()}
+	case p.accept("NOT", "LIKE"):
+		return &DoesNotMatchNode{Left: left, Right: p.parseAdditive()}
+	case p.accept("BETWEEN"):
+		return p.parseBetween(left)
+	case p.accept("NOT", "BETWEEN"):
+		return NewNotNode(p.parseBetween(left))
+	}
+	return left
+}
+
+// parseComparand parses the right side of a comparison
+// which may be quantified with ANY, SOME or ALL
+func (p *parser) parseComparand() Visitable {
+	switch {
+	case p.accept("ANY"), p.accept("SOME"):
+		return NewAnyNode(p.parseSubquery())
+	case p.accept("ALL"):
+		return NewAllNode(p.parseSubquery())
+	}
+	return p.parseAdditive()
+}
+
+func (p *parser) parseSubquery() Visitable {
+	p.expect("(")
+	query := p.parseQueryExpression()
+	p.expect(")")
+	return query
+}
+
+func (p *parser) parseInList() []Visitable {
+	if p.isAt(1, "SELECT") || p.isAt(1, "WITH") {
+		return []Visitable{p.parseSubquery()}
+	}
+	p.expect("(")
+	if p.accept(")") {
+		return []Visitable{}
+	}
+	exprs := p.parseExpressions()
+	p.expect(")")
+	return exprs
+}
+
+func (p *parser) parseBetween(left Visitable) Visitable {
+	low := p.parseAdditive()
+	p.expect("AND")
+	high := p.parseAdditive()
+	return &BetweenNode{Left: left, Right: &AndNode{Children: &[]Visitable{low, high}}}
+}
+
+func (p *parser) parseAdditive() Visitable {
+	left := p.parseMultiplicative()
+	for {
+		t := p.peek()
+		if p.accept("+") || p.accept("-") || (p.dialect != "mysql" && p.accept("||")) {
+			left = &InfixOperationNode{Operator: Sql(t.text), Left: left, Right: p.parseMultiplicative()}
+			continue
+		}
+		return left
+	}
+}
+
+func (p *parser) parseMultiplicative() Visitable {
+	left := p.parseUnary()
+	for {
+		t := p.peek()
+		if p.accept("*") || p.accept("/") || p.accept("%") {
+			left = &InfixOperationNode{Operator: Sql(t.text), Left: left, Right: p.parseUnary()}
+			continue
+		}
+		return left
+	}
+}
+
+func (p *parser) parseUnary() Visitable {
+	if p.accept("-") {
+		if p.peek().kind == tokenNumber {
+			return Sql("-" + p.next().text)
+		}
+		return &InfixOperationNode{Operator: Sql("-"), Left: Sql(0), Right: p.parseUnary()}
+	}
+	p.accept("+")
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() Visitable {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		return Sql(t.text)
+	case tokenString:
+		p.next()
+		return &QuotedNode{Raw: t.text}
+	case tokenParam:
+		p.next()
+		return NewBindParamNode(t.text)
+	case tokenEOF:
+		p.fail("unexpected end of input")
+	}
+
+	switch {
+	case p.accept("("):
+		if p.is("SELECT") || p.is("WITH") {
+			query := p.parseQueryExpression()
+			p.expect(")")
+			return &GroupingNode{Expr: []Visitable{query}}
+		}
+		exprs := p.parseExpressions()
+		p.expect(")")
+		if len(exprs) > 1 {
+			return Tuple(exprs...)
+		}
+		return &GroupingNode{Expr: exprs}
+	case p.accept("*"):
+		return Star()
+	case p.accept("NULL"):
+		return Sql("NULL")
+	case p.accept("TRUE"):
+		return &TrueNode{}
+	case p.accept("FALSE"):
+		return &FalseNode{}
+	case p.accept("EXISTS"):
+		return NewExistsNode(p.parseSubquery())
+	case p.accept("CASE"):
+		return p.parseCase()
+	case p.is("EXTRACT") && p.isAt(1, "("):
+		p.next()
+		p.next()
+		field := Sql(p.next().text)
+		p.expect("FROM")
+		expr := p.parseExpression()
+		p.expect(")")
+		return &ExtractNode{Expressions: []Visitable{expr}, Field: &field}
+	}
+
+	if t.kind == tokenWord && niladicFunctions[strings.ToUpper(t.text)] {
+		p.next()
+		return Sql(strings.ToUpper(t.text))
+	}
+
+	name := p.identifier()
+	if p.is("(") && t.kind == tokenWord {
+		return p.parseFunction(name)
+	}
+	if !p.is(".") {
+		return &UnqualifiedColumnNode{Expr: &AttributeNode{Name: Sql(name)}}
+	}
+	names := []string{name}
+	for p.accept(".") {
+		if p.accept("*") {
+			return Sql(fmt.Sprintf("%s.*", p.engine.Visitor().QuoteTableName(p.qualifiedTable(names))))
+		}
+		names = append(names, p.identifier())
+	}
+	return p.qualifiedTable(names[:len(names)-1]).Attr(names[len(names)-1])
+}
+
+func (p *parser) parseCase() Visitable {
+	node := &CaseNode{}
+	if !p.is("WHEN") {
+		node.Case = p.parseExpression()
+	}
+	for p.accept("WHEN") {
+		condition := p.parseExpression()
+		p.expect("THEN")
+		node.When(condition, p.parseExpression())
+	}
+	if len(node.Conditions) == 0 {
+		p.fail("expected WHEN but found %s", p.peek())
+	}
+	if p.accept("ELSE") {
+		node.Else(p.parseExpression())
+	}
+	p.expect("END")
+	return node
+}
+
+func (p *parser) parseFunction(name string) Visitable {
+	p.expect("(")
+	fn := FunctionNode{}
+	fn.DistinctValues = p.accept("DISTINCT")
+	upper := strings.ToUpper(name)
+	var separator Visitable
+	if !p.is(")") {
+		for {
+			if p.accept("*") {
+				fn.Expressions = append(fn.Expressions, Star())
+			} else {
+				fn.Expressions = append(fn.Expressions, p.parseExpression())
+			}
+			if upper == "GROUP_CONCAT" && p.accept("SEPARATOR") {
+				separator = p.parsePrimary()
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	p.expect(")")
+	if p.accept("FILTER") {
+		p.expect("(", "WHERE")
+		fn.FilterExpr = p.parseExpression()
+		p.expect(")")
+	}
+
+	var node Visitable
+	switch upper {
+	case "COUNT":
+		node = (*CountNode)(&fn)
+	case "SUM":
+		node = (*SumNode)(&fn)
+	case "MAX":
+		node = (*MaxNode)(&fn)
+	case "MIN":
+		node = (*MinNode)(&fn)
+	case "AVG":
+		node = (*AvgNode)(&fn)
+	case "STRING_AGG":
+		node = (*StringAggNode)(&fn)
+	case "ARRAY_AGG":
+		node = (*ArrayAggNode)(&fn)
+	case "JSON_AGG":
+		node = (*JsonAggNode)(&fn)
+	case "BOOL_AND":
+		node = (*BoolAndNode)(&fn)
+	case "BOOL_OR":
+		node = (*BoolOrNode)(&fn)
+	case "GROUP_CONCAT":
+		if separator == nil {
+			separator = &QuotedNode{Raw: ","}
+		}
+		fn.Expressions = append(fn.Expressions, separator)
+		node = (*StringAggNode)(&fn)
+	default:
+		node = &NamedFunctionNode{Name: &SqlLiteralNode{Raw: name}, FunctionNode: fn}
+	}
+
+	if p.accept("OVER") {
+		if p.accept("(") {
+			window := &WindowNode{}
+			window.Partitions, window.Orders, window.Framing = p.parseWindowDefinition()
+			p.expect(")")
+			return &OverNode{Left: node, Right: window}
+		}
+		return &OverNode{Left: node, Right: Sql(p.aliasName())}
+	}
+	return node
+}
+
+func (p *parser) parseInsert() Visitable {
+	p.expect("INSERT", "INTO")
+	mgr := NewInsertManager(p.engine)
+	table := p.parseTableName(p.identifier())
+	mgr.Into(table)
+
+	columns := []*AttributeNode{}
+	if p.accept("(") {
+		for {
+			columns = append(columns, table.Attr(p.identifier()))
+			if !p.accept(",") {
+				break
+			}
+		}
+		p.expect(")")
+	}
+
+	if p.is("SELECT") || p.is("WITH") {
+		p.fail("INSERT with a query is not supported")
+	}
+	p.expect("VALUES")
+	rows := [][]interface{}{}
+	for {
+		p.expect("(")
+		values := []interface{}{}
+		for {
+			values = append(values, p.parseInsertValue())
+			if !p.accept(",") {
+				break
+			}
+		}
+		p.expect(")")
+		if len(columns) > 0 && len(columns) != len(values) {
+			p.fail("expected %d values but found %d", len(columns), len(values))
+		}
+		rows = append(rows, values)
+		if !p.accept(",") {
+			break
+		}
+	}
+	mgr.Ast.Columns = &columns
+	mgr.SetValues(&ValuesNode{Values: rows[0], Columns: columns, Rows: rows[1:]})
+	return mgr
+}
+
+// numberValue keeps a number as written, assignments and insert
+// values would quote it as a string if it was left as raw SQL
+func numberValue(node Visitable) (json.Number, bool) {
+	literal, ok := node.(SqlLiteralNode)
+	if ok && (integerLiteral.MatchString(literal.Raw) || decimalLiteral.MatchString(literal.Raw)) {
+		return json.Number(literal.Raw), true
+	}
+	return "", false
+}
+
+// literal insert values are quoted by the connector like values
+// given to the manager, other expressions are kept as nodes
+func (p *parser) parseInsertValue() interface{} {
+	node := p.parseExpression()
+	switch n := node.(type) {
+	case *TrueNode:
+		return true
+	case *FalseNode:
+		return false
+	case SqlLiteralNode:
+		if n.Raw == "NULL" {
+			return nil
+		}
+		if number, ok := numberValue(n); ok {
+			return number
+		}
+		// a pointer is rendered as raw SQL instead of a quoted value
+		return &n
+	}
+	return node
+}
+
+func (p *parser) parseUpdate() Visitable {
+	p.expect("UPDATE")
+	mgr := NewUpdateManager(p.engine)
+	table := p.parseTableName(p.identifier())
+	mgr.Table(table)
+	p.expect("SET")
+	for {
+		column := table.Attr(p.identifier())
+		p.expect("=")
+		value := p.parseExpression()
+		if literal, ok := value.(SqlLiteralNode); ok && literal.Raw == "NULL" {
+			value = nil
+		}
+		if number, ok := numberValue(value); ok {
+			value = NewValueNode(number)
+		}
+		mgr.Set(column, value)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if p.accept("WHERE") {
+		mgr.Where(p.parseExpression())
+	}
+	return mgr
+}
+
+func (p *parser) parseDelete() Visitable {
+	p.expect("DELETE", "FROM")
+	mgr := NewDeleteManager(p.engine)
+	mgr.From(p.parseTableName(p.identifier()))
+	if p.accept("WHERE") {
+		mgr.Where(p.parseExpression())
+	}
+	return mgr
+}
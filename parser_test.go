@@ -0,0 +1,176 @@
+package rel_test
+
+import (
+	"strings"
+
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parse", func() {
+	roundTrip := func(dialect string, sql string) string {
+		mgr, err := Parse(dialect, sql)
+		Expect(err).NotTo(HaveOccurred())
+		return mgr.(TreeManager).ToSql()
+	}
+
+	It("returns a select manager for a select statement", func() {
+		mgr, err := Parse("postgresql", `SELECT "users"."id" FROM "users" WHERE "users"."age" > 21`)
+		Expect(err).NotTo(HaveOccurred())
+		users := NewTable("users")
+		Expect(mgr).To(BeAssignableToTypeOf(&SelectManager{}))
+		expected := users.Select(users.Attr("id")).Where(users.Attr("age").Gt(Sql(21)))
+		Expect(StructurallyEqual(mgr.(*SelectManager).Ast, expected.Ast)).To(BeTrue())
+	})
+
+	It("renders parsed statements like the managers that built them", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		mgr := users.Select(users.Attr("id"), Star().Count().As(Sql("total")))
+		mgr.Join(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr.OuterJoin(NewTable("teams")).On(users.Attr("team_id").Eq(NewTable("teams").Attr("id")))
+		mgr.Where(&GroupingNode{Expr: []Visitable{&OrNode{
+			Left:  users.Attr("name").Matches(Sql("a%")),
+			Right: users.Attr("age").In([]Visitable{Sql(1), Sql(2)}),
+		}}})
+		mgr.Where(users.Attr("deleted_at").Eq(nil))
+		mgr.Group(users.Attr("id"))
+		mgr.Having(Star().Count().Gt(Sql(5)))
+		mgr.Order(users.Attr("id").Desc())
+		mgr.Take(10).Skip(20)
+		visitors := map[string]Visitor{
+			"postgresql": &PostgreSQLVisitor{Conn: DefaultConnector{}},
+			"mysql":      &MysqlVisitor{Conn: DefaultConnector{}},
+			"sqlite":     &SQLiteVisitor{Conn: DefaultConnector{}},
+		}
+		for dialect, visitor := range visitors {
+			sql := visitor.Accept(mgr.Ast)
+			input := sql
+			// MySQL reads double quotes as strings
+			if dialect == "mysql" {
+				input = strings.Replace(sql, `"`, "`", -1)
+			}
+			Expect(roundTrip(dialect, input)).To(Equal(sql))
+		}
+	})
+
+	It("parses set operations and common table expressions", func() {
+		sql := `( SELECT * FROM "users" WHERE "users"."age" < 18 UNION ALL SELECT * FROM "users" WHERE "users"."age" > 99 )`
+		mgr, err := Parse("postgresql", sql)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mgr).To(BeAssignableToTypeOf(&MultiStatementManager{}))
+		Expect(mgr.(*MultiStatementManager).ToSql()).To(Equal(sql))
+
+		cte := `WITH RECURSIVE "replies" AS ( SELECT "comments"."id" FROM "comments" UNION SELECT "comments"."id" FROM "comments" INNER JOIN "replies" ON "comments"."parent_id" = "replies"."id" ) SELECT * FROM "replies"`
+		Expect(roundTrip("postgresql", cte)).To(Equal(cte))
+	})
+
+	It("selects from compound queries with clauses of their own", func() {
+		Expect(roundTrip("postgresql", `SELECT "name" FROM "users" UNION SELECT "name" FROM "admins" ORDER BY "name" LIMIT 5`)).
+			To(Equal(`SELECT * FROM (SELECT "name" FROM "users" UNION SELECT "name" FROM "admins") "rel_compound" ORDER BY "name" LIMIT 5`))
+		Expect(roundTrip("postgresql", `WITH "a" AS (SELECT 1 AS n) SELECT "n" FROM "a" UNION ALL SELECT 2`)).
+			To(Equal(`WITH "a" AS (SELECT 1 AS n) SELECT * FROM (SELECT "n" FROM "a" UNION ALL SELECT 2) "rel_compound"`))
+		_, err := Parse("postgresql", `SELECT 1 UNION SELECT 2 FOR UPDATE`)
+		Expect(err).To(MatchError(ContainSubstring("locking a compound query")))
+	})
+
+	It("resolves table aliases and subqueries", func() {
+		sql := `SELECT u.id FROM users u WHERE EXISTS (SELECT 1 FROM posts p WHERE p.user_id = u.id)`
+		expected := `SELECT "u"."id" FROM "users" "u" WHERE EXISTS (SELECT 1 FROM "posts" "p" WHERE "p"."user_id" = "u"."id")`
+		Expect(roundTrip("postgresql", sql)).To(Equal(expected))
+	})
+
+	It("parses VALUES lists, table functions and multiple sources", func() {
+		sql := `SELECT * FROM (VALUES (1, 'a'), (2, 'b')) "v"("id", "name"), generate_series(1, 3) "g"("n"), "users" WHERE "users"."id" = "v"."id"`
+		Expect(roundTrip("postgresql", sql)).To(Equal(sql))
+		Expect(roundTrip("mysql", "SELECT * FROM (VALUES ROW(1), ROW(2)) AS v(id)")).
+			To(Equal(`SELECT * FROM (VALUES ROW(1), ROW(2)) v("id")`))
+	})
+
+	It("parses qualified table names", func() {
+		sql := `SELECT "public"."users"."id", "u"."name" FROM "public"."users" INNER JOIN "shop"."dbo"."users" "u" ON "u"."id" = "public"."users"."id"`
+		Expect(roundTrip("postgresql", sql)).To(Equal(sql))
+		Expect(roundTrip("mysql", "DELETE FROM `shop`.`users` WHERE `id` = 1")).
+			To(Equal(`DELETE FROM "shop"."users" WHERE "id" = 1`))
+	})
+
+	It("parses functions, windows and case expressions", func() {
+		sql := `SELECT ROW_NUMBER() OVER (PARTITION BY "users"."team_id" ORDER BY "users"."id" DESC), CASE WHEN "users"."age" < 18 THEN 'minor' ELSE 'adult' END AS kind, SUM(DISTINCT "users"."score") FILTER (WHERE "users"."active" = TRUE) FROM "users"`
+		Expect(roundTrip("postgresql", sql)).To(Equal(sql))
+	})
+
+	It("parses insert, update and delete statements", func() {
+		Expect(roundTrip("postgresql", `INSERT INTO "users" ("name", "age") VALUES ('bob', 42)`)).
+			To(Equal(`INSERT INTO "users" ("name", "age") VALUES ('bob', 42)`))
+		Expect(roundTrip("postgresql", `UPDATE users SET name = 'bob', age = age + 1 WHERE id = $1`)).
+			To(Equal(`UPDATE "users" SET "name" = 'bob', "age" = "age" + 1 WHERE "id" = $1`))
+		Expect(roundTrip("mysql", "DELETE FROM `users` WHERE `users`.`id` IN (1, 2)")).
+			To(Equal(`DELETE FROM "users" WHERE "users"."id" IN (1, 2)`))
+	})
+
+	It("inserts multiple rows and expressions", func() {
+		Expect(roundTrip("postgresql", `INSERT INTO "users" ("name", "age") VALUES ('bob', 42), ('it''s', -1)`)).
+			To(Equal(`INSERT INTO "users" ("name", "age") VALUES ('bob', 42), ('it''s', -1)`))
+		Expect(roundTrip("postgresql", `INSERT INTO "users" ("name", "created_at") VALUES (LOWER('BOB'), CURRENT_TIMESTAMP)`)).
+			To(Equal(`INSERT INTO "users" ("name", "created_at") VALUES (LOWER('BOB'), CURRENT_TIMESTAMP)`))
+		_, err := Parse("postgresql", `INSERT INTO "users" ("name") VALUES ('a'), ('b', 1)`)
+		Expect(err).To(MatchError(ContainSubstring("expected 1 values but found 2")))
+	})
+
+	It("reads numbers with an exponent", func() {
+		Expect(roundTrip("postgresql", `SELECT 1.5e3, 2E-2 AS e3`)).To(Equal(`SELECT 1.5e3, 2E-2 AS e3`))
+		Expect(roundTrip("postgresql", `UPDATE "t" SET "a" = 1e+10`)).To(Equal(`UPDATE "t" SET "a" = 1e+10`))
+	})
+
+	It("reads double quoted strings in MySQL", func() {
+		Expect(roundTrip("mysql", `SELECT * FROM users WHERE name = "bob" AND note = "it's ""x"""`)).
+			To(Equal(`SELECT * FROM "users" WHERE "name" = 'bob' AND "note" = 'it''s "x"'`))
+		Expect(roundTrip("postgresql", `SELECT "bob" FROM "t"`)).To(Equal(`SELECT "bob" FROM "t"`))
+	})
+
+	It("reads the dialect specific syntax", func() {
+		Expect(roundTrip("mysql", `SELECT * FROM users LIMIT 5, 10`)).
+			To(Equal(`SELECT * FROM "users" LIMIT 10 OFFSET 5`))
+		Expect(roundTrip("mysql", `SELECT * FROM users WHERE a = 1 || b = 2`)).
+			To(Equal(`SELECT * FROM "users" WHERE "a" = 1 OR "b" = 2`))
+	})
+
+	It("keeps numbers in assignments and insert values", func() {
+		Expect(roundTrip("postgresql", `UPDATE "t" SET "a" = 1, "b" = -1.50, "c" = NULL`)).
+			To(Equal(`UPDATE "t" SET "a" = 1, "b" = -1.50, "c" = NULL`))
+		Expect(roundTrip("postgresql", `INSERT INTO "t" ("a", "b") VALUES (12345678901234567890, 1.5)`)).
+			To(Equal(`INSERT INTO "t" ("a", "b") VALUES (12345678901234567890, 1.5)`))
+
+		mgr, err := Parse("mysql", `UPDATE t SET a = 2.25`)
+		Expect(err).NotTo(HaveOccurred())
+		data, err := EncodeJSON(mgr)
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := DecodeJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded.(*UpdateManager).ToSql()).To(Equal(`UPDATE "t" SET "a" = 2.25`))
+	})
+
+	It("reads doubled quotes in identifiers", func() {
+		Expect(roundTrip("postgresql", `SELECT "a""b" FROM "t"`)).To(Equal(`SELECT "a""b" FROM "t"`))
+		Expect(roundTrip("postgresql", `SELECT "x" AS "a""b" FROM "t"`)).To(Equal(`SELECT "x" AS "a""b" FROM "t"`))
+		Expect(roundTrip("mysql", "SELECT `a``b` FROM `t`")).To(Equal(`SELECT "a` + "`" + `b" FROM "t"`))
+	})
+
+	It("reads backslash escapes in MySQL strings", func() {
+		Expect(roundTrip("mysql", `SELECT * FROM t WHERE a = 'it\'s' AND b = 'c:\\'`)).
+			To(Equal(`SELECT * FROM "t" WHERE "a" = 'it\'s' AND "b" = 'c:\\'`))
+		_, err := Parse("postgresql", `SELECT * FROM t WHERE a = 'c:\' AND b = 'x'`)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = Parse("mysql", `SELECT * FROM t WHERE a = 'c:\'`)
+		Expect(err).To(MatchError(ContainSubstring("unterminated string")))
+	})
+
+	It("reports errors with their position", func() {
+		_, err := Parse("postgresql", `SELECT FROM WHERE`)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*ParseError).Pos).To(Equal(12))
+		_, err = Parse("oracle", `SELECT 1`)
+		Expect(err).To(HaveOccurred())
+	})
+})
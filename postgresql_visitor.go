@@ -31,6 +31,12 @@ func (v *PostgreSQLVisitor) Visit(visitable Visitable) string {
 		return visitationJoinSourceNode(v, node)
 	case *EqualityNode:
 		return visitationEqualityNode(v, node)
+	case *RollupNode:
+		return visitationRollupNode(v, node)
+	case *CubeNode:
+		return visitationCubeNode(v, node)
+	case *GroupingSetsNode:
+		return visitationGroupingSetsNode(v, node)
 	case *HavingNode:
 		return visitationHavingNode(v, node)
 	case *AttributeNode:
@@ -39,6 +45,10 @@ func (v *PostgreSQLVisitor) Visit(visitable Visitable) string {
 		return visitationGroupNode(v, node)
 	case *ExistsNode:
 		return visitationExistsNode(v, node)
+	case *AnyNode:
+		return visitationAnyNode(v, node)
+	case *AllNode:
+		return visitationAllNode(v, node)
 	case *AsNode:
 		return visitationAsNode(v, node)
 	case *LessThanNode:
@@ -69,6 +79,8 @@ func (v *PostgreSQLVisitor) Visit(visitable Visitable) string {
 		return visitationTableAliasNode(v, node)
 	case *InnerJoinNode:
 		return visitationInnerJoinNode(v, node)
+	case *TupleNode:
+		return visitationTupleNode(v, node)
 	case *GroupingNode:
 		return visitationGroupingNode(v, node)
 	case *NamedWindowNode:
@@ -79,6 +91,8 @@ func (v *PostgreSQLVisitor) Visit(visitable Visitable) string {
 		return visitationRowsNode(v, node)
 	case *LockNode:
 		return visitationLockNode(v, node)
+	case *RowLockNode:
+		return visitationRowLockNode(v, node)
 	case *PrecedingNode:
 		return visitationPrecedingNode(v, node)
 	case *FollowingNode:
@@ -108,12 +122,30 @@ func (v *PostgreSQLVisitor) Visit(visitable Visitable) string {
 		return visitationSelectCoreNode(v, node)
 	case *NotEqualNode:
 		return visitationNotEqualNode(v, node)
+	case *IsDistinctFromNode:
+		return v.visitIsDistinctFromNode(node)
+	case *IsNotDistinctFromNode:
+		return v.visitIsNotDistinctFromNode(node)
 	case *NotNode:
 		return visitationNotNode(v, node)
 	case *GreaterThanOrEqualNode:
 		return visitationGreaterThanOrEqualNode(v, node)
 	case *LessThanOrEqualNode:
 		return visitationLessThanOrEqualNode(v, node)
+	case *CaseNode:
+		return visitationCaseNode(v, node)
+	case *WhenNode:
+		return visitationWhenNode(v, node)
+	case *StringAggNode:
+		return visitationStringAggNode(v, node)
+	case *ArrayAggNode:
+		return visitationArrayAggNode(v, node)
+	case *JsonAggNode:
+		return visitationJsonAggNode(v, node)
+	case *BoolAndNode:
+		return visitationBoolAndNode(v, node)
+	case *BoolOrNode:
+		return visitationBoolOrNode(v, node)
 	case *OrNode:
 		return visitationOrNode(v, node)
 	case *AvgNode:
@@ -138,8 +170,14 @@ func (v *PostgreSQLVisitor) Visit(visitable Visitable) string {
 		return visitationExtractNode(v, node)
 	case *InfixOperationNode:
 		return visitationInfixOperationNode(v, node)
+	case *BindParamNode:
+		return visitationBindParamNode(v, node)
 	case *QuotedNode:
 		return visitationQuotedNode(v, node)
+	case *ValueNode:
+		return visitationValueNode(v, node)
+	case attributeColumn:
+		return visitationAttributeNode(v, node.attributeNode())
 	case *OverNode:
 		return visitationOverNode(v, node)
 	case *AssignmentNode:
@@ -147,9 +185,17 @@ func (v *PostgreSQLVisitor) Visit(visitable Visitable) string {
 	case *UnqualifiedColumnNode:
 		return visitationUnqualifiedColumnNode(v, node)
 	case *DistinctOnNode:
-		return v.visitDistinctOnNode(node)
+		return visitationDistinctOnNode(v, node)
 	case *OuterJoinNode:
 		return visitationOuterJoinNode(v, node)
+	case *RightOuterJoinNode:
+		return visitationRightOuterJoinNode(v, node)
+	case *FullOuterJoinNode:
+		return visitationFullOuterJoinNode(v, node)
+	case *CommaJoinNode:
+		return visitationCommaJoinNode(v, node)
+	case *ValuesListNode:
+		return visitationValuesListNode(v, node, "")
 	case *OffsetNode:
 		return visitationOffsetNode(v, node)
 	case *LimitNode:
@@ -181,6 +227,9 @@ func (v PostgreSQLVisitor) QuoteTableName(visitable Visitable) string {
 			return alias.Name
 		}
 	}
+	if table, ok := visitable.(*Table); ok && table != nil {
+		return quoteQualifiedTableName(v.Conn, table)
+	}
 	return v.Conn.QuoteTableName(visitable.String())
 }
 
@@ -192,6 +241,10 @@ func (v PostgreSQLVisitor) QuoteColumnName(literal SqlLiteralNode) string {
 	return v.Conn.QuoteColumnName(literal.Raw)
 }
 
+func (v *PostgreSQLVisitor) Capabilities() Capabilities {
+	return PostgreSQLCapabilities
+}
+
 func (v PostgreSQLVisitor) visitMatchesNode(node *MatchesNode) string {
 	var buf bytes.Buffer
 	buf.WriteString(v.Visit(node.Left))
@@ -208,10 +261,18 @@ func (v PostgreSQLVisitor) visitDoesNotMatchNode(node *DoesNotMatchNode) string
 	return buf.String()
 }
 
-func (v PostgreSQLVisitor) visitDistinctOnNode(node *DistinctOnNode) string {
+func (v PostgreSQLVisitor) visitIsDistinctFromNode(node *IsDistinctFromNode) string {
 	var buf bytes.Buffer
-	buf.WriteString("DISTINCT ON ( ")
-	buf.WriteString(v.Visit(node.Expr))
-	buf.WriteString(" )")
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(" IS DISTINCT FROM ")
+	buf.WriteString(v.Visit(node.Right))
+	return buf.String()
+}
+
+func (v PostgreSQLVisitor) visitIsNotDistinctFromNode(node *IsNotDistinctFromNode) string {
+	var buf bytes.Buffer
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(" IS NOT DISTINCT FROM ")
+	buf.WriteString(v.Visit(node.Right))
 	return buf.String()
 }
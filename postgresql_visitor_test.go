@@ -25,4 +25,27 @@ var _ = Describe("PostgreSQLVisitor", func() {
 		Expect(visitor.Accept(core)).To(Equal(`SELECT DISTINCT`))
 	})
 
+	It("should support is distinct from", func() {
+		users := NewTable("users")
+		node := users.Attr("a").IsDistinctFrom(users.Attr("b"))
+		Expect(visitor.Accept(node)).To(Equal(`"users"."a" IS DISTINCT FROM "users"."b"`))
+	})
+
+	It("should support is not distinct from", func() {
+		users := NewTable("users")
+		node := users.Attr("a").IsNotDistinctFrom(users.Attr("b"))
+		Expect(visitor.Accept(node)).To(Equal(`"users"."a" IS NOT DISTINCT FROM "users"."b"`))
+	})
+
+	It("should support key share locks", func() {
+		stmt := NewSelectStatementNode()
+		stmt.Lock = NewLockNode(NewRowLockNode(LockKeyShare))
+		Expect(visitor.Accept(stmt)).To(Equal(`SELECT FOR KEY SHARE`))
+	})
+	It("should support distinct on multiple expressions", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).DistinctOn(users.Attr("team_id"), users.Attr("role"))
+		stmt := mgr.Ast
+		Expect(visitor.Accept(stmt)).To(Equal(`SELECT DISTINCT ON ( "users"."team_id", "users"."role" ) * FROM "users"`))
+	})
 })
@@ -14,7 +14,23 @@ type Predicator interface {
 	GtAll(...Visitable) *GroupingNode
 	In([]Visitable) Visitable
 	NotIn([]Visitable) Visitable
+	InQuery(*SelectManager) *InNode
+	NotInQuery(*SelectManager) *NotInNode
+	EqAnyQuery(*SelectManager) *EqualityNode
+	EqAllQuery(*SelectManager) *EqualityNode
+	NotEqAnyQuery(*SelectManager) *NotEqualNode
+	NotEqAllQuery(*SelectManager) *NotEqualNode
+	LtAnyQuery(*SelectManager) *LessThanNode
+	LtAllQuery(*SelectManager) *LessThanNode
+	LtEqAnyQuery(*SelectManager) *LessThanOrEqualNode
+	LtEqAllQuery(*SelectManager) *LessThanOrEqualNode
+	GtAnyQuery(*SelectManager) *GreaterThanNode
+	GtAllQuery(*SelectManager) *GreaterThanNode
+	GtEqAnyQuery(*SelectManager) *GreaterThanOrEqualNode
+	GtEqAllQuery(*SelectManager) *GreaterThanOrEqualNode
 	NotEq(Visitable) *NotEqualNode
+	IsDistinctFrom(Visitable) *IsDistinctFromNode
+	IsNotDistinctFrom(Visitable) *IsNotDistinctFromNode
 	Matches(SqlLiteralNode) *MatchesNode
 	DoesNotMatch(SqlLiteralNode) *DoesNotMatchNode
 	Visitable
@@ -122,7 +138,7 @@ func predicationIn(node Predicator, visitables []Visitable) Visitable {
 	in := &InNode{Left: node}
 	for _, v := range visitables {
 		switch val := v.(type) {
-		case SelectManager:
+		case *SelectManager:
 			in.Right = append(in.Right, val.Ast)
 		default:
 			in.Right = append(in.Right, v)
@@ -135,7 +151,7 @@ func predicationNotIn(node Predicator, visitables []Visitable) Visitable {
 	notin := &NotInNode{Left: node}
 	for _, v := range visitables {
 		switch val := v.(type) {
-		case SelectManager:
+		case *SelectManager:
 			notin.Right = append(notin.Right, val.Ast)
 		default:
 			notin.Right = append(notin.Right, v)
@@ -144,6 +160,62 @@ func predicationNotIn(node Predicator, visitables []Visitable) Visitable {
 	return notin
 }
 
+func predicationInQuery(node Predicator, mgr *SelectManager) *InNode {
+	return &InNode{Left: node, Right: []Visitable{mgr.Ast}}
+}
+
+func predicationNotInQuery(node Predicator, mgr *SelectManager) *NotInNode {
+	return &NotInNode{Left: node, Right: []Visitable{mgr.Ast}}
+}
+
+func predicationEqAnyQuery(node Predicator, mgr *SelectManager) *EqualityNode {
+	return node.Eq(NewAnyNode(mgr.Ast))
+}
+
+func predicationEqAllQuery(node Predicator, mgr *SelectManager) *EqualityNode {
+	return node.Eq(NewAllNode(mgr.Ast))
+}
+
+func predicationNotEqAnyQuery(node Predicator, mgr *SelectManager) *NotEqualNode {
+	return node.NotEq(NewAnyNode(mgr.Ast))
+}
+
+func predicationNotEqAllQuery(node Predicator, mgr *SelectManager) *NotEqualNode {
+	return node.NotEq(NewAllNode(mgr.Ast))
+}
+
+func predicationLtAnyQuery(node Predicator, mgr *SelectManager) *LessThanNode {
+	return node.Lt(NewAnyNode(mgr.Ast))
+}
+
+func predicationLtAllQuery(node Predicator, mgr *SelectManager) *LessThanNode {
+	return node.Lt(NewAllNode(mgr.Ast))
+}
+
+func predicationLtEqAnyQuery(node Predicator, mgr *SelectManager) *LessThanOrEqualNode {
+	return node.LtEq(NewAnyNode(mgr.Ast))
+}
+
+func predicationLtEqAllQuery(node Predicator, mgr *SelectManager) *LessThanOrEqualNode {
+	return node.LtEq(NewAllNode(mgr.Ast))
+}
+
+func predicationGtAnyQuery(node Predicator, mgr *SelectManager) *GreaterThanNode {
+	return node.Gt(NewAnyNode(mgr.Ast))
+}
+
+func predicationGtAllQuery(node Predicator, mgr *SelectManager) *GreaterThanNode {
+	return node.Gt(NewAllNode(mgr.Ast))
+}
+
+func predicationGtEqAnyQuery(node Predicator, mgr *SelectManager) *GreaterThanOrEqualNode {
+	return node.GtEq(NewAnyNode(mgr.Ast))
+}
+
+func predicationGtEqAllQuery(node Predicator, mgr *SelectManager) *GreaterThanOrEqualNode {
+	return node.GtEq(NewAllNode(mgr.Ast))
+}
+
 func predicationNotInAny(node Predicator, visitableslices ...[]Visitable) Visitable {
 	visitables := make([]Visitable, len(visitableslices))
 	for i, visitableslice := range visitableslices {
@@ -201,6 +273,20 @@ func predicationNotEqAll(node Predicator, visitable ...Visitable) *GroupingNode
 	return predicationGroupAll(node, visitable...)
 }
 
+func predicationIsDistinctFrom(node Predicator, v Visitable) *IsDistinctFromNode {
+	return &IsDistinctFromNode{
+		Left:  node,
+		Right: v,
+	}
+}
+
+func predicationIsNotDistinctFrom(node Predicator, v Visitable) *IsNotDistinctFromNode {
+	return &IsNotDistinctFromNode{
+		Left:  node,
+		Right: v,
+	}
+}
+
 func predicationDoesNotMatch(node Predicator, literal SqlLiteralNode) *DoesNotMatchNode {
 	var v Visitable = &QuotedNode{Raw: literal.Raw}
 	return &DoesNotMatchNode{
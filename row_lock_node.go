@@ -0,0 +1,69 @@
+package rel
+
+type LockStrength int
+
+const (
+	LockUpdate LockStrength = iota
+	LockNoKeyUpdate
+	LockShare
+	LockKeyShare
+)
+
+func (s LockStrength) String() string {
+	switch s {
+	case LockNoKeyUpdate:
+		return "NO KEY UPDATE"
+	case LockShare:
+		return "SHARE"
+	case LockKeyShare:
+		return "KEY SHARE"
+	default:
+		return "UPDATE"
+	}
+}
+
+type LockWait int
+
+const (
+	LockWaitDefault LockWait = iota
+	LockNoWait
+	LockSkipLocked
+)
+
+func (w LockWait) String() string {
+	switch w {
+	case LockNoWait:
+		return "NOWAIT"
+	case LockSkipLocked:
+		return "SKIP LOCKED"
+	default:
+		return ""
+	}
+}
+
+// A structured row locking clause such as FOR UPDATE OF "jobs" SKIP LOCKED
+type RowLockNode struct {
+	Strength LockStrength
+	Wait     LockWait
+	Tables   []Visitable
+	BaseVisitable
+}
+
+func NewRowLockNode(strength LockStrength) *RowLockNode {
+	return &RowLockNode{Strength: strength}
+}
+
+func (node *RowLockNode) NoWait() *RowLockNode {
+	node.Wait = LockNoWait
+	return node
+}
+
+func (node *RowLockNode) SkipLocked() *RowLockNode {
+	node.Wait = LockSkipLocked
+	return node
+}
+
+func (node *RowLockNode) Of(visitables ...Visitable) *RowLockNode {
+	node.Tables = append(node.Tables, visitables...)
+	return node
+}
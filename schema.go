@@ -0,0 +1,325 @@
+package rel
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ColumnType is the kind of value stored in a declared column,
+// the empty type is unknown and compatible with every value
+type ColumnType string
+
+const (
+	TypeInteger   ColumnType = "integer"
+	TypeFloat     ColumnType = "float"
+	TypeDecimal   ColumnType = "decimal"
+	TypeText      ColumnType = "text"
+	TypeBoolean   ColumnType = "boolean"
+	TypeTimestamp ColumnType = "timestamp"
+	TypeDate      ColumnType = "date"
+	TypeTime      ColumnType = "time"
+	TypeBinary    ColumnType = "binary"
+	TypeJSON      ColumnType = "json"
+	TypeUUID      ColumnType = "uuid"
+)
+
+// category groups the types which can be compared with each other
+func (t ColumnType) category() string {
+	switch t {
+	case TypeInteger, TypeFloat, TypeDecimal:
+		return "numeric"
+	case TypeTimestamp, TypeDate, TypeTime:
+		return "temporal"
+	}
+	return string(t)
+}
+
+// ColumnDefinition declares a column of a table, Default is
+// the expression used when a row is inserted without a value
+type ColumnDefinition struct {
+	Name       string
+	Type       ColumnType
+	Nullable   bool
+	PrimaryKey bool
+	Default    Visitable
+}
+
+// WithColumns declares the columns of a table, attributes of other
+// columns fail to render and are reported by Attribute and Lint
+func WithColumns(columns ...*ColumnDefinition) TableOption {
+	return func(t *Table) {
+		t.Columns = append(t.Columns, columns...)
+	}
+}
+
+// Declared reports whether the columns of the table are known
+func (t *Table) Declared() bool {
+	return len(t.Columns) > 0
+}
+
+// ColumnDefinition returns the declared column with the name
+func (t *Table) ColumnDefinition(name string) (*ColumnDefinition, bool) {
+	for _, column := range t.Columns {
+		if column.Name == name {
+			return column, true
+		}
+	}
+	return nil, false
+}
+
+// PrimaryKey returns the declared primary key columns in order
+func (t *Table) PrimaryKey() []*ColumnDefinition {
+	columns := []*ColumnDefinition{}
+	for _, column := range t.Columns {
+		if column.PrimaryKey {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// Attribute is Attr returning an error for an unknown column of a
+// declared table instead of a node which panics when it is rendered
+func (t *Table) Attribute(name string) (*AttributeNode, error) {
+	if err := t.checkColumn(name); err != nil {
+		return nil, err
+	}
+	return t.Attr(name), nil
+}
+
+func (t *Table) checkColumn(name string) error {
+	if !t.Declared() || name == "*" {
+		return nil
+	}
+	if _, ok := t.ColumnDefinition(name); !ok {
+		return fmt.Errorf("rel: unknown column %s.%s", t.Name, name)
+	}
+	return nil
+}
+
+// declaredTable returns the declared table of an attribute relation
+func declaredTable(relation Visitable) *Table {
+	if alias, ok := relation.(*TableAliasNode); ok {
+		relation = alias.Relation
+	}
+	if t, ok := relation.(*Table); ok && t != nil && t.Declared() {
+		return t
+	}
+	return nil
+}
+
+// columnError is the error of an attribute naming an unknown column
+func columnError(node *AttributeNode) error {
+	if t := declaredTable(node.Relation); t != nil {
+		return t.checkColumn(node.Name.Raw)
+	}
+	return nil
+}
+
+// columnDefinition returns the declared column of an attribute
+func columnDefinition(node *AttributeNode) *ColumnDefinition {
+	if t := declaredTable(node.Relation); t != nil {
+		if column, ok := t.ColumnDefinition(node.Name.Raw); ok {
+			return column
+		}
+	}
+	return nil
+}
+
+// mustKnowColumn panics with the error of an unknown column, a
+// typo is never sent to the database
+func mustKnowColumn(node *AttributeNode) {
+	if err := columnError(node); err != nil {
+		panic(err)
+	}
+}
+
+// A Catalog is a registry of declared tables
+type Catalog struct {
+	tables map[string]*Table
+}
+
+func NewCatalog() *Catalog {
+	return &Catalog{tables: make(map[string]*Table)}
+}
+
+// Declare creates a table and registers it by its qualified name,
+// declaring a name again replaces the table
+func (c *Catalog) Declare(name string, options ...TableOption) *Table {
+	table := NewTable(name, options...)
+	c.tables[qualifiedTableKey(table)] = table
+	return table
+}
+
+// Table returns a declared table by its name, qualified with the
+// schema and database the table was declared with
+func (c *Catalog) Table(name string) (*Table, bool) {
+	table, ok := c.tables[name]
+	return table, ok
+}
+
+// Tables returns the declared tables ordered by qualified name
+func (c *Catalog) Tables() []*Table {
+	keys := []string{}
+	for key := range c.tables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tables := []*Table{}
+	for _, key := range keys {
+		tables = append(tables, c.tables[key])
+	}
+	return tables
+}
+
+func qualifiedTableKey(table *Table) string {
+	names := []string{}
+	for _, name := range []string{table.Database, table.Schema, table.Name} {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+func lintUnknownColumn(node Visitable, ctx LintContext) string {
+	if attr, ok := node.(*AttributeNode); ok {
+		if err := columnError(attr); err != nil {
+			return strings.TrimPrefix(err.Error(), "rel: ")
+		}
+	}
+	return ""
+}
+
+// lintTypeMismatch compares declared columns with other columns
+// and with literal values, unknown types are never reported
+func lintTypeMismatch(node Visitable, ctx LintContext) string {
+	switch node := node.(type) {
+	case *EqualityNode:
+		return typeMismatch(node.Left, node.Right)
+	case *NotEqualNode:
+		return typeMismatch(node.Left, node.Right)
+	case *GreaterThanNode:
+		return typeMismatch(node.Left, node.Right)
+	case *GreaterThanOrEqualNode:
+		return typeMismatch(node.Left, node.Right)
+	case *LessThanNode:
+		return typeMismatch(node.Left, node.Right)
+	case *LessThanOrEqualNode:
+		return typeMismatch(node.Left, node.Right)
+	case *IsDistinctFromNode:
+		return typeMismatch(node.Left, node.Right)
+	case *IsNotDistinctFromNode:
+		return typeMismatch(node.Left, node.Right)
+	case *BetweenNode:
+		if bounds, ok := node.Right.(*AndNode); ok && bounds.Children != nil {
+			return typeMismatch(node.Left, *bounds.Children...)
+		}
+	case *InNode:
+		return typeMismatch(node.Left, node.Right...)
+	case *NotInNode:
+		return typeMismatch(node.Left, node.Right...)
+	}
+	return ""
+}
+
+func typeMismatch(left Visitable, rights ...Visitable) string {
+	for _, right := range rights {
+		if message := comparisonMismatch(left, right); message != "" {
+			return message
+		}
+		if message := comparisonMismatch(right, left); message != "" {
+			return message
+		}
+	}
+	return ""
+}
+
+// comparisonMismatch reports a declared column on the left which
+// cannot be compared with the value on the right
+func comparisonMismatch(left Visitable, right Visitable) string {
+	attr, ok := left.(*AttributeNode)
+	if !ok {
+		return ""
+	}
+	column := columnDefinition(attr)
+	if column == nil || column.Type == "" {
+		return ""
+	}
+	name := attr.Name.Raw
+	if t := declaredTable(attr.Relation); t != nil {
+		name = t.Name + "." + name
+	}
+	if other, ok := right.(*AttributeNode); ok {
+		if otherColumn := columnDefinition(other); otherColumn != nil && otherColumn.Type != "" &&
+			otherColumn.Type.category() != column.Type.category() {
+			return fmt.Sprintf("%s column %s is compared with %s column %s.%s",
+				column.Type, name, otherColumn.Type, declaredTable(other.Relation).Name, other.Name.Raw)
+		}
+		return ""
+	}
+	literal := literalType(right)
+	if literal == "" || literal.category() == column.Type.category() {
+		return ""
+	}
+	// strings are read as UUID and JSON values by the databases
+	if literal == TypeText && (column.Type == TypeUUID || column.Type == TypeJSON) {
+		return ""
+	}
+	return fmt.Sprintf("%s column %s is compared with a %s literal", column.Type, name, literal)
+}
+
+var (
+	integerLiteral = regexp.MustCompile(`^-?[0-9]+$`)
+	decimalLiteral = regexp.MustCompile(`^-?([0-9]*\.[0-9]+([eE][-+]?[0-9]+)?|[0-9]+[eE][-+]?[0-9]+)$`)
+)
+
+// literalType returns the type of a literal value, NULL and
+// other expressions have no type
+func literalType(node Visitable) ColumnType {
+	switch node := node.(type) {
+	case *QuotedNode:
+		return TypeText
+	case *SqlLiteralNode:
+		return literalType(*node)
+	case SqlLiteralNode:
+		raw := strings.TrimSpace(node.Raw)
+		switch {
+		case len(raw) >= 2 && strings.HasPrefix(raw, "'") && strings.HasSuffix(raw, "'"):
+			return TypeText
+		case integerLiteral.MatchString(raw):
+			return TypeInteger
+		case decimalLiteral.MatchString(raw):
+			return TypeDecimal
+		case strings.EqualFold(raw, "true") || strings.EqualFold(raw, "false"):
+			return TypeBoolean
+		}
+	case *TrueNode, *FalseNode:
+		return TypeBoolean
+	case *ValueNode:
+		switch value := node.Value.(type) {
+		case json.Number:
+			if integerLiteral.MatchString(value.String()) {
+				return TypeInteger
+			}
+			return TypeDecimal
+		case bool:
+			return TypeBoolean
+		case string:
+			return TypeText
+		case int64, uint64:
+			return TypeInteger
+		case float32, float64:
+			return TypeFloat
+		case time.Time:
+			return TypeTimestamp
+		case []byte:
+			return TypeBinary
+		}
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schema", func() {
+	catalog := NewCatalog()
+	users := catalog.Declare("users", WithColumns(
+		&ColumnDefinition{Name: "id", Type: TypeInteger, PrimaryKey: true},
+		&ColumnDefinition{Name: "email", Type: TypeText},
+		&ColumnDefinition{Name: "active", Type: TypeBoolean, Default: &TrueNode{}},
+		&ColumnDefinition{Name: "created_at", Type: TypeTimestamp, Nullable: true},
+	))
+	posts := catalog.Declare("posts", WithSchema("blog"), WithColumns(
+		&ColumnDefinition{Name: "id", Type: TypeUUID, PrimaryKey: true},
+		&ColumnDefinition{Name: "user_id", Type: TypeInteger},
+		&ColumnDefinition{Name: "published_at", Type: TypeTimestamp, Nullable: true},
+	))
+
+	rules := func(issues LintIssues) []string {
+		names := []string{}
+		for _, issue := range issues {
+			names = append(names, issue.Rule)
+		}
+		return names
+	}
+
+	It("registers declared tables by qualified name", func() {
+		table, ok := catalog.Table("blog.posts")
+		Expect(ok).To(BeTrue())
+		Expect(table).To(BeIdenticalTo(posts))
+		_, ok = catalog.Table("posts")
+		Expect(ok).To(BeFalse())
+		Expect(catalog.Tables()).To(Equal([]*Table{posts, users}))
+	})
+
+	It("describes the declared columns", func() {
+		Expect(users.Declared()).To(BeTrue())
+		Expect(NewTable("users").Declared()).To(BeFalse())
+		column, ok := users.ColumnDefinition("created_at")
+		Expect(ok).To(BeTrue())
+		Expect(column.Type).To(Equal(TypeTimestamp))
+		Expect(column.Nullable).To(BeTrue())
+		Expect(users.PrimaryKey()).To(HaveLen(1))
+		Expect(users.PrimaryKey()[0].Name).To(Equal("id"))
+	})
+
+	It("returns an error for unknown columns of declared tables", func() {
+		attr, err := users.Attribute("email")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(attr.Name.Raw).To(Equal("email"))
+
+		_, err = users.Attribute("emial")
+		Expect(err).To(MatchError("rel: unknown column users.emial"))
+
+		_, err = NewTable("users").Attribute("emial")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = users.Attribute("*")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports unknown columns when linting", func() {
+		mgr := users.Select(users.Attr("id")).Where(users.Attr("emial").Eq(Sql("'a@example.com'")))
+		issues := Lint(mgr)
+		Expect(rules(issues)).To(Equal([]string{"unknown-column"}))
+		Expect(issues.Err()).To(MatchError(ContainSubstring("unknown column users.emial")))
+
+		alias := users.Alias()
+		Expect(rules(Lint(users.Select(alias.Attr("nmae"))))).To(Equal([]string{"unknown-column"}))
+	})
+
+	It("fails to render unknown columns", func() {
+		mgr := users.Select(users.Attr("emial"))
+		Expect(func() { mgr.ToSql() }).To(PanicWith(MatchError("rel: unknown column users.emial")))
+		alias := users.Alias()
+		Expect(func() { users.Select(alias.Attr("nmae")).ToSql() }).To(PanicWith(MatchError("rel: unknown column users.nmae")))
+		Expect(users.Select(users.Attr("email")).ToSql()).To(Equal(`SELECT "users"."email" FROM "users"`))
+	})
+
+	It("reports comparisons of incompatible types", func() {
+		mgr := users.Where(users.Attr("created_at").Gt(Sql("'2024-01-01'")))
+		issues := Lint(mgr)
+		Expect(rules(issues)).To(Equal([]string{"type-mismatch"}))
+		Expect(issues[0].Message).To(Equal("timestamp column users.created_at is compared with a text literal"))
+
+		mgr = users.Where(users.Attr("id").In([]Visitable{Sql(1), &QuotedNode{Raw: "2"}}))
+		Expect(rules(Lint(mgr))).To(Equal([]string{"type-mismatch"}))
+
+		mgr = users.Select().Where(Sql("'x'").Eq(users.Attr("active")))
+		Expect(rules(Lint(mgr))).To(Equal([]string{"type-mismatch"}))
+
+		mgr = users.Select(users.Attr("id")).InnerJoin(posts).On(posts.Attr("id").Eq(users.Attr("id")))
+		Expect(Lint(mgr)[0].Message).To(Equal("uuid column posts.id is compared with integer column users.id"))
+	})
+
+	It("accepts comparisons of compatible types", func() {
+		mgr := users.Select(users.Attr("id")).InnerJoin(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr.Where(users.Attr("active").Eq(&TrueNode{}))
+		mgr.Where(users.Attr("id").Gt(Sql("1.5")))
+		mgr.Where(posts.Attr("id").Eq(Sql("'8c2f0e1c-0d4e-4a55-9a1b-2d6f3c1e7b90'")))
+		mgr.Where(posts.Attr("published_at").Gt(users.Attr("created_at")))
+		mgr.Where(users.Attr("created_at").Eq(nil))
+		mgr.Where(users.Attr("email").Eq(NewBindParamNode("$1")))
+		Expect(Lint(mgr)).To(BeEmpty())
+	})
+
+	It("keeps the columns of tables encoded as JSON", func() {
+		data, err := EncodeJSON(users.Select(users.Attr("id")))
+		Expect(err).NotTo(HaveOccurred())
+		decoded, err := DecodeJSON(data)
+		Expect(err).NotTo(HaveOccurred())
+		table := decoded.(*SelectManager).Ast.Cores[0].Source.Left.(*Table)
+		Expect(table.Columns).To(HaveLen(4))
+		Expect(*table.Columns[2]).To(Equal(*users.Columns[2]))
+	})
+})
@@ -23,3 +23,36 @@ func NewSelectCoreNode() *SelectCoreNode {
 func (node *SelectCoreNode) SetFrom(v Visitable) {
 	node.Source.Left = v
 }
+
+// isAggregate reports whether the core collapses rows, in which case
+// the rows cannot be counted by replacing the projections
+func (node *SelectCoreNode) isAggregate() bool {
+	return (node.Groups != nil && len(*node.Groups) > 0) ||
+		node.SetQuantifier != nil ||
+		node.Having != nil
+}
+
+// copy returns a shallow copy of the core with its own slices so
+// appending to the copy does not modify the original
+func (node *SelectCoreNode) copy() *SelectCoreNode {
+	core := *node
+	if node.Source != nil {
+		core.Source = &JoinSource{
+			Left:  node.Source.Left,
+			Right: append([]Visitable{}, node.Source.Right...),
+		}
+	}
+	core.Selections = copyVisitables(node.Selections)
+	core.Wheres = copyVisitables(node.Wheres)
+	core.Groups = copyVisitables(node.Groups)
+	core.Windows = copyVisitables(node.Windows)
+	return &core
+}
+
+func copyVisitables(visitables *[]Visitable) *[]Visitable {
+	if visitables == nil {
+		return nil
+	}
+	c := append([]Visitable{}, *visitables...)
+	return &c
+}
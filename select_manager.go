@@ -44,30 +44,52 @@ func (mgr *SelectManager) Select(visitables ...Visitable) *SelectManager {
 			mgr.Ctx.Selections = &[]Visitable{}
 		}
 
-		*mgr.Ctx.Selections = append(*mgr.Ctx.Selections, selection)
+		*mgr.Ctx.Selections = append(*mgr.Ctx.Selections, columnNode(selection))
 	}
 	return mgr
 }
 
-func (mgr *SelectManager) From(table interface{}) *SelectManager {
-	var v Visitable
+// From sets the sources of the statement, a *Table, Table or table
+// name, or any relation such as an aliased subquery, VALUES list or
+// table function. Further sources are listed after a comma
+func (mgr *SelectManager) From(tables ...interface{}) *SelectManager {
+	if len(tables) == 0 {
+		return mgr
+	}
+	mgr.Ctx.Source.Left = fromSource(tables[0])
+
+	// replace the sources of an earlier call, keeping the joins
+	right := []Visitable{}
+	for _, table := range tables[1:] {
+		right = append(right, &CommaJoinNode{Left: fromSource(table)})
+	}
+	for _, join := range mgr.Ctx.Source.Right {
+		if _, ok := join.(*CommaJoinNode); !ok {
+			right = append(right, join)
+		}
+	}
+	mgr.Ctx.Source.Right = right
+	return mgr
+}
+
+func fromSource(table interface{}) Visitable {
 	switch t := table.(type) {
 	case *Table:
-		v = t
+		return t
 	case Table:
-		v = &t
+		return &t
 	case string:
-		v = NewTable(t)
+		return NewTable(t)
+	case Visitable:
+		return t
 	}
-	mgr.Ctx.Source.Left = v
-	return mgr
+	return nil
 }
 
-func (mgr *SelectManager) As(name string) *TableAliasNode {
-	return &TableAliasNode{
-		Relation: &GroupingNode{Expr: []Visitable{mgr.Ast}},
-		Name:     name,
-	}
+// As aliases the statement as a derived table, the columns
+// rename the columns of the statement
+func (mgr *SelectManager) As(name string, columns ...string) *TableAliasNode {
+	return NewTableAliasNode(&GroupingNode{Expr: []Visitable{mgr.Ast}}, name, columns...)
 }
 
 func (mgr *SelectManager) On(visitables ...Visitable) *SelectManager {
@@ -80,6 +102,10 @@ func (mgr *SelectManager) On(visitables ...Visitable) *SelectManager {
 			val.Right = mgr.NewOnNode(mgr.collapse(visitables...))
 		case *OuterJoinNode:
 			val.Right = mgr.NewOnNode(mgr.collapse(visitables...))
+		case *RightOuterJoinNode:
+			val.Right = mgr.NewOnNode(mgr.collapse(visitables...))
+		case *FullOuterJoinNode:
+			val.Right = mgr.NewOnNode(mgr.collapse(visitables...))
 		default:
 			log.Fatalf("Unable to call On with input type %T", val)
 		}
@@ -98,6 +124,10 @@ func (mgr *SelectManager) Using(str string) *SelectManager {
 			val.Right = &UsingNode{Expr: &QuotedNode{Raw: str}}
 		case *OuterJoinNode:
 			val.Right = &UsingNode{Expr: &QuotedNode{Raw: str}}
+		case *RightOuterJoinNode:
+			val.Right = &UsingNode{Expr: &QuotedNode{Raw: str}}
+		case *FullOuterJoinNode:
+			val.Right = &UsingNode{Expr: &QuotedNode{Raw: str}}
 		default:
 			log.Fatalf("Unable to call On with input type %T", val)
 		}
@@ -120,6 +150,16 @@ func (mgr *SelectManager) OuterJoin(visitable Visitable) *SelectManager {
 	return mgr
 }
 
+func (mgr *SelectManager) RightOuterJoin(visitable Visitable) *SelectManager {
+	mgr.Ctx.Source.Right = append(mgr.Ctx.Source.Right, &RightOuterJoinNode{Left: visitable})
+	return mgr
+}
+
+func (mgr *SelectManager) FullOuterJoin(visitable Visitable) *SelectManager {
+	mgr.Ctx.Source.Right = append(mgr.Ctx.Source.Right, &FullOuterJoinNode{Left: visitable})
+	return mgr
+}
+
 func (mgr *SelectManager) Lock(node SqlLiteralNode) *SelectManager {
 	mgr.Ast.Lock = NewLockNode(node)
 	return mgr
@@ -130,6 +170,16 @@ func (mgr *SelectManager) LockForUpdate() *SelectManager {
 	return mgr
 }
 
+// RowLock sets a structured locking clause on the statement, the returned
+// node can be used to set the wait policy and the locked tables. MySQL
+// renders the key lock modes as stronger locks, SQLite has no locks.
+// Validate reports the lock modes a dialect does not have
+func (mgr *SelectManager) RowLock(strength LockStrength) *RowLockNode {
+	lock := NewRowLockNode(strength)
+	mgr.Ast.Lock = NewLockNode(lock)
+	return lock
+}
+
 func (mgr *SelectManager) Take(i int) *SelectManager {
 	return mgr.Limit(i)
 }
@@ -143,25 +193,77 @@ func (mgr *SelectManager) Exists() *ExistsNode {
 	return NewExistsNode(mgr.Ast)
 }
 
+func (mgr *SelectManager) NotExists() *NotNode {
+	return NewNotNode(mgr.Exists())
+}
+
+// CountQuery returns a new manager that counts the rows matched by the
+// statement without Orders, Limit, Offset or Lock. Grouped, distinct and
+// compound statements are counted from a derived table. The original
+// statement is not modified
+func (mgr *SelectManager) CountQuery() *SelectManager {
+	count := &CountNode{Expressions: []Visitable{Star()}}
+	cores := make([]*SelectCoreNode, len(mgr.Ast.Cores))
+	for i, core := range mgr.Ast.Cores {
+		cores[i] = core.copy()
+	}
+
+	if len(cores) == 1 && !cores[0].isAggregate() {
+		stmt := &SelectStatementNode{Cores: cores, With: mgr.Ast.With}
+		stmt.Cores[0].Selections = &[]Visitable{count}
+		return &SelectManager{Engine: mgr.Engine, Ast: stmt, Ctx: stmt.Cores[0]}
+	}
+
+	counter := NewSelectManager(mgr.Engine, nil)
+	counter.Ast.With = mgr.Ast.With
+	counter.Ctx.SetFrom(&TableAliasNode{
+		Relation: &GroupingNode{Expr: []Visitable{&SelectStatementNode{Cores: cores}}},
+		Name:     "subquery",
+	})
+	return counter.Select(count)
+}
+
 func (mgr *SelectManager) Order(visitables ...Visitable) *SelectManager {
 	if len(visitables) > 0 {
 		if mgr.Ast.Orders == nil {
 			mgr.Ast.Orders = &[]Visitable{}
 		}
 		for _, v := range visitables {
-			*mgr.Ast.Orders = append(*mgr.Ast.Orders, v)
+			*mgr.Ast.Orders = append(*mgr.Ast.Orders, columnNode(v))
 		}
 	}
 	return mgr
 }
 
+// SeekAfter restricts the statement to rows that follow the cursor in the
+// current Orders, the cursor holds one non NULL value per order expression.
+// The statement is left unchanged when an error is returned
+func (mgr *SelectManager) SeekAfter(cursor ...Visitable) error {
+	return mgr.seek(cursor, true)
+}
+
+// SeekBefore restricts the statement to rows that precede the cursor in the
+// current Orders. Reverse the Orders to fetch the page closest to the cursor
+func (mgr *SelectManager) SeekBefore(cursor ...Visitable) error {
+	return mgr.seek(cursor, false)
+}
+
+func (mgr *SelectManager) seek(cursor []Visitable, after bool) error {
+	predicate, err := seekPredicate(mgr.Ast.Orders, cursor, after)
+	if err != nil {
+		return err
+	}
+	mgr.Where(predicate)
+	return nil
+}
+
 func (mgr *SelectManager) Where(visitable Visitable) *SelectManager {
 	if mgr.Ctx.Wheres == nil {
 		mgr.Ctx.Wheres = &[]Visitable{}
 	}
 
-	if expr, ok := visitable.(SelectManager); ok {
-		*mgr.Ctx.Wheres = append(*mgr.Ctx.Wheres, expr.Ast)
+	if expr, ok := visitable.(*SelectManager); ok {
+		*mgr.Ctx.Wheres = append(*mgr.Ctx.Wheres, &GroupingNode{Expr: []Visitable{expr.Ast}})
 	} else {
 		*mgr.Ctx.Wheres = append(*mgr.Ctx.Wheres, visitable)
 	}
@@ -179,7 +281,7 @@ func (mgr *SelectManager) Group(visitables ...Visitable) *SelectManager {
 			mgr.Ctx.Groups = &[]Visitable{}
 		}
 		for _, v := range visitables {
-			*mgr.Ctx.Groups = append(*mgr.Ctx.Groups, NewGroupNode(v))
+			*mgr.Ctx.Groups = append(*mgr.Ctx.Groups, NewGroupNode(columnNode(v)))
 		}
 	}
 	return mgr
@@ -220,6 +322,21 @@ func (mgr *SelectManager) Distinct() *SelectManager {
 	return mgr
 }
 
+// DistinctOn keeps the first row of each group of rows with equal
+// expressions, the first row is determined by the statement Orders
+func (mgr *SelectManager) DistinctOn(visitables ...Visitable) *SelectManager {
+	if len(visitables) == 1 {
+		mgr.Ctx.SetQuantifier = NewDistinctOnNode(columnNode(visitables[0]))
+	} else {
+		expressions := []Visitable{}
+		for _, v := range visitables {
+			expressions = append(expressions, columnNode(v))
+		}
+		mgr.Ctx.SetQuantifier = NewDistinctOnNode(Tuple(expressions...))
+	}
+	return mgr
+}
+
 func (mgr *SelectManager) NotDistinct() *SelectManager {
 	mgr.Ctx.SetQuantifier = nil
 	return mgr
@@ -256,3 +373,8 @@ func (mgr *SelectManager) collapse(visitables ...Visitable) Visitable {
 	}
 	return v
 }
+
+// Clone returns a deep copy, modifying it never affects mgr
+func (mgr *SelectManager) Clone() *SelectManager {
+	return Clone(mgr).(*SelectManager)
+}
@@ -157,6 +157,22 @@ var _ = Describe("SelectManager", func() {
 		Expect(sql).To(Equal(expected))
 	})
 
+	It("joins the conditions of several wheres with AND", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Where(users.Attr("age").Gt(Sql(18))).Where(users.Attr("active").Eq(Sql(true)))
+		expected := `SELECT * FROM "users" WHERE "users"."age" > 18 AND "users"."active" = true`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("selects from aliased tables and derived tables", func() {
+		users := NewTable("users")
+		mgr := NewSelectManager(RelEngine, nil).Project(Star())
+		mgr.Ctx.SetFrom(&TableAliasNode{Relation: users, Name: "u"})
+		Expect(mgr.ToSql()).To(Equal(`SELECT * FROM "users" u`))
+		mgr.Ctx.SetFrom(&TableAliasNode{Relation: &GroupingNode{Expr: []Visitable{users.Select(Star()).Ast}}, Name: "subquery"})
+		Expect(mgr.ToSql()).To(Equal(`SELECT * FROM (SELECT * FROM "users") subquery`))
+	})
+
 	It("has an group method", func() {
 		users := NewTable("users")
 		mgr := users.Group(users.Attr("id"))
@@ -361,4 +377,298 @@ var _ = Describe("SelectManager", func() {
 		expected := `WITH RECURSIVE "replies" AS ( SELECT "comments"."id", "comments"."parent_id" FROM "comments" WHERE "comments"."id" = 42 UNION SELECT "comments"."id", "comments"."parent_id" FROM "comments" INNER JOIN "replies" ON "comments"."parent_id" = "replies"."id" ) SELECT * FROM "replies"`
 		Expect(sql).To(Equal(expected))
 	})
+	It("has a NotExists method", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		sub := posts.Select(Star()).Where(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr := users.Select(Star()).Where(sub.NotExists())
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE NOT EXISTS (SELECT * FROM "posts" WHERE "posts"."user_id" = "users"."id")`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("qualifies correlated references with the outer table alias", func() {
+		outer := NewTable("users")
+		outer.SetTableAlias("u1")
+		inner := NewTable("users")
+		inner.SetTableAlias("u2")
+		sub := inner.Select(Star()).Where(inner.Attr("manager_id").Eq(outer.Attr("id")))
+		mgr := outer.Select(outer.Attr("id")).Where(sub.Exists())
+		sql := mgr.ToSql()
+		expected := `SELECT "u1"."id" FROM "users" "u1" WHERE EXISTS (SELECT * FROM "users" "u2" WHERE "u2"."manager_id" = "u1"."id")`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("accepts a subquery in the In predication", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		sub := posts.Select(posts.Attr("user_id"))
+		mgr := users.Select(Star()).Where(users.Attr("id").In([]Visitable{sub}))
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE "users"."id" IN (SELECT "posts"."user_id" FROM "posts")`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("accepts a subquery with the InQuery predication", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		sub := posts.Select(posts.Attr("user_id"))
+		mgr := users.Select(Star()).Where(users.Attr("id").InQuery(sub))
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE "users"."id" IN (SELECT "posts"."user_id" FROM "posts")`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("accepts a subquery with the NotInQuery predication", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		sub := posts.Select(posts.Attr("user_id"))
+		mgr := users.Select(Star()).Where(users.Attr("id").NotInQuery(sub))
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE "users"."id" NOT IN (SELECT "posts"."user_id" FROM "posts")`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("accepts a subquery with the EqAnyQuery predication", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		sub := posts.Select(posts.Attr("user_id"))
+		mgr := users.Select(Star()).Where(users.Attr("id").EqAnyQuery(sub))
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE "users"."id" = ANY (SELECT "posts"."user_id" FROM "posts")`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("accepts a subquery with the GtAllQuery predication", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		sub := posts.Select(posts.Attr("score"))
+		mgr := users.Select(Star()).Where(users.Attr("score").GtAllQuery(sub))
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE "users"."score" > ALL (SELECT "posts"."score" FROM "posts")`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("accepts a SelectManager in Where", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Where(Select(Sql("TRUE")))
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE (SELECT TRUE)`
+		Expect(sql).To(Equal(expected))
+	})
+	It("seeks after a cursor with a row value comparison", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Order(users.Attr("created_at"), users.Attr("id")).Take(10)
+		Expect(mgr.SeekAfter(Sql(100), Sql(5))).To(Succeed())
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE ("users"."created_at", "users"."id") > (100, 5) ORDER BY "users"."created_at", "users"."id" LIMIT 10`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("seeks after a cursor in a filtered statement", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Where(users.Attr("active").Eq(Sql(true))).Order(users.Attr("id"))
+		Expect(mgr.SeekAfter(Sql(5))).To(Succeed())
+		expected := `SELECT * FROM "users" WHERE "users"."active" = true AND "users"."id" > 5 ORDER BY "users"."id"`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("seeks before a cursor when every order is descending", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Order(users.Attr("created_at").Desc(), users.Attr("id").Desc())
+		Expect(mgr.SeekBefore(Sql(100), Sql(5))).To(Succeed())
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE ("users"."created_at", "users"."id") > (100, 5) ORDER BY "users"."created_at" DESC, "users"."id" DESC`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("expands the seek predicate for mixed directions", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Order(users.Attr("score").Desc(), users.Attr("id").Asc())
+		Expect(mgr.SeekAfter(Sql(10), Sql(5))).To(Succeed())
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE ("users"."score" < 10 OR ("users"."score" = 10 AND "users"."id" > 5)) ORDER BY "users"."score" DESC, "users"."id" ASC`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("seeks on a single order", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Order(users.Attr("id").Desc())
+		Expect(mgr.SeekAfter(Sql(5))).To(Succeed())
+		sql := mgr.ToSql()
+		expected := `SELECT * FROM "users" WHERE "users"."id" < 5 ORDER BY "users"."id" DESC`
+		Expect(sql).To(Equal(expected))
+	})
+
+	It("refuses to seek without orders or with the wrong number of values", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star())
+		Expect(mgr.SeekAfter(Sql(5))).To(MatchError("rel: seeking requires the statement to be ordered"))
+		mgr.Order(users.Attr("id"))
+		Expect(mgr.SeekBefore(Sql(5), Sql(6))).To(MatchError("rel: seeking requires 1 cursor values, got 2"))
+		Expect(mgr.ToSql()).To(Equal(`SELECT * FROM "users" ORDER BY "users"."id"`))
+	})
+
+	It("refuses NULL cursor values", func() {
+		users := NewTable("users")
+		mgr := users.Select(Star()).Order(users.Attr("name"), users.Attr("id"))
+		Expect(mgr.SeekAfter(nil, Sql(5))).To(MatchError("rel: cursor value 1 is NULL, seek on non null columns"))
+		Expect(mgr.SeekAfter(Sql("a"), Sql("NULL"))).To(HaveOccurred())
+	})
+	It("derives a count query", func() {
+		users := NewTable("users")
+		mgr := users.Select(users.Attr("id"), users.Attr("name")).Where(users.Attr("age").Gt(Sql(18)))
+		mgr.Order(users.Attr("id")).Take(10).Skip(20).LockForUpdate()
+		original := mgr.ToSql()
+		count := mgr.CountQuery()
+		count.Where(users.Attr("active").Eq(Sql(true)))
+		expected := `SELECT COUNT(*) FROM "users" WHERE "users"."age" > 18 AND "users"."active" = true`
+		Expect(count.ToSql()).To(Equal(expected))
+		Expect(mgr.ToSql()).To(Equal(original))
+	})
+
+	It("derives a count query from a derived table when grouped", func() {
+		users := NewTable("users")
+		mgr := users.Select(users.Attr("team_id")).Group(users.Attr("team_id")).Order(users.Attr("team_id"))
+		original := mgr.ToSql()
+		expected := `SELECT COUNT(*) FROM (SELECT "users"."team_id" FROM "users" GROUP BY "users"."team_id") subquery`
+		Expect(mgr.CountQuery().ToSql()).To(Equal(expected))
+		Expect(mgr.ToSql()).To(Equal(original))
+	})
+
+	It("derives a count query from a derived table when distinct", func() {
+		users := NewTable("users")
+		mgr := users.Select(users.Attr("name")).Distinct()
+		expected := `SELECT COUNT(*) FROM (SELECT DISTINCT "users"."name" FROM "users") subquery`
+		Expect(mgr.CountQuery().ToSql()).To(Equal(expected))
+	})
+
+	It("derives a count query from a compound statement", func() {
+		users := NewTable("users")
+		m1 := users.Select(users.Attr("id")).Where(users.Attr("age").Lt(Sql(18)))
+		m2 := users.Select(users.Attr("id")).Where(users.Attr("age").Gt(Sql(99)))
+		union := m1.Union(m1.Ast, m2.Ast)
+		expected := `SELECT COUNT(*) FROM (SELECT "users"."id" FROM "users" WHERE "users"."age" < 18 UNION SELECT "users"."id" FROM "users" WHERE "users"."age" > 99) subquery`
+		Expect(union.CountQuery().ToSql()).To(Equal(expected))
+		Expect(MysqlVisitor{Conn: DefaultConnector{}}.Accept(union.CountQuery().Ast)).To(Equal(expected))
+	})
+	It("has a row lock method", func() {
+		jobs := NewTable("jobs")
+		mgr := jobs.Select(Star()).Take(1)
+		mgr.RowLock(LockUpdate).SkipLocked()
+		expected := `SELECT * FROM "jobs" LIMIT 1 FOR UPDATE SKIP LOCKED`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("has a row lock method that locks specific tables", func() {
+		jobs := NewTable("jobs")
+		queues := NewTable("queues")
+		queues.SetTableAlias("q")
+		mgr := jobs.Select(Star()).Join(queues).On(queues.Attr("id").Eq(jobs.Attr("queue_id")))
+		mgr.RowLock(LockNoKeyUpdate).Of(jobs, queues).NoWait()
+		expected := `SELECT * FROM "jobs" INNER JOIN "queues" "q" ON "q"."id" = "jobs"."queue_id" FOR NO KEY UPDATE OF "jobs", "q" NOWAIT`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+	It("can group by a rollup", func() {
+		sales := NewTable("sales")
+		mgr := sales.Select(sales.Attr("year"), sales.Attr("month"), Grouping(sales.Attr("month")), Sum(sales.Attr("total")))
+		mgr.Group(Rollup(sales.Attr("year"), sales.Attr("month")))
+		expected := `SELECT "sales"."year", "sales"."month", GROUPING("sales"."month"), SUM("sales"."total") FROM "sales" GROUP BY ROLLUP ("sales"."year", "sales"."month")`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can group by a cube", func() {
+		sales := NewTable("sales")
+		mgr := sales.Select(Star()).Group(Cube(sales.Attr("region"), sales.Attr("product")))
+		expected := `SELECT * FROM "sales" GROUP BY CUBE ("sales"."region", "sales"."product")`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can group by grouping sets", func() {
+		sales := NewTable("sales")
+		mgr := sales.Select(Star()).Group(GroupingSets(
+			Tuple(sales.Attr("region"), sales.Attr("product")),
+			sales.Attr("region"),
+			Tuple(),
+		))
+		expected := `SELECT * FROM "sales" GROUP BY GROUPING SETS (("sales"."region", "sales"."product"), "sales"."region", ())`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+	It("has a DistinctOn method", func() {
+		posts := NewTable("posts")
+		mgr := posts.Select(posts.Attr("user_id"), posts.Attr("title").As(Sql("title")))
+		mgr.DistinctOn(posts.Attr("user_id")).Order(posts.Attr("user_id"), posts.Attr("created_at").Desc())
+		expected := `SELECT DISTINCT ON ( "posts"."user_id" ) "posts"."user_id", "posts"."title" AS title FROM "posts" ORDER BY "posts"."user_id", "posts"."created_at" DESC`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can be cloned without leaking conditions", func() {
+		users := NewTable("users")
+		base := users.Select(users.Attr("id")).Where(users.Attr("active").Eq(Sql(true)))
+		branch := base.Clone()
+		branch.Where(users.Attr("age").Gt(Sql(21))).Order(users.Attr("id").Desc())
+		Expect(base.ToSql()).To(Equal(`SELECT "users"."id" FROM "users" WHERE "users"."active" = true`))
+		Expect(branch.ToSql()).To(Equal(`SELECT "users"."id" FROM "users" WHERE "users"."active" = true AND "users"."age" > 21 ORDER BY "users"."id" DESC`))
+	})
+
+	It("keeps the engine when cloned", func() {
+		users := NewTable("users")
+		mgr := users.Select(users.Attr("id"))
+		Expect(mgr.Clone().Engine).To(Equal(mgr.Engine))
+	})
+
+	It("can deep copy a single node", func() {
+		users := NewTable("users")
+		node := users.Attr("id").In([]Visitable{Sql(1), Sql(2)})
+		clone := Clone(node).(*InNode)
+		clone.Right[0] = Sql(3)
+		Expect(RelEngine.Visitor().Accept(node)).To(Equal(`"users"."id" IN (1, 2)`))
+	})
+
+	It("selects from an aliased subquery", func() {
+		users := NewTable("users")
+		adults := users.Select(users.Attr("id"), users.Attr("name")).Where(users.Attr("age").GtEq(Sql(18))).As("adults", "id", "name")
+		mgr := Select(adults.Attr("name")).From(adults)
+		Expect(mgr.ToSql()).To(Equal(`SELECT adults."name" FROM (SELECT "users"."id", "users"."name" FROM "users" WHERE "users"."age" >= 18) adults("id", "name")`))
+	})
+
+	It("selects from the result of a set operation", func() {
+		users := NewTable("users")
+		ids := users.Select().Union(users.Select(users.Attr("id")).Ast, users.Select(users.Attr("parent_id")).Ast).As("ids")
+		mgr := Select(Star()).From(ids)
+		Expect(mgr.ToSql()).To(Equal(`SELECT * FROM ( SELECT "users"."id" FROM "users" UNION SELECT "users"."parent_id" FROM "users" ) ids`))
+	})
+
+	It("selects from a VALUES list", func() {
+		v := NewValuesListNode([]interface{}{1, "a"}).Row(2, NewBindParamNode("$1")).As("v", "id", "name")
+		mgr := Select(v.Attr("name")).From(v)
+		Expect(mgr.ToSql()).To(Equal(`SELECT v."name" FROM (VALUES (1, 'a'), (2, $1)) v("id", "name")`))
+
+		mysql := MysqlVisitor{Conn: DefaultConnector{}}
+		Expect(mysql.Accept(mgr.Ast)).To(HaveSuffix(`FROM (VALUES ROW(1, 'a'), ROW(2, $1)) v("id", "name")`))
+		sqlite := SQLiteVisitor{Conn: DefaultConnector{}}
+		Expect(sqlite.Accept(mgr.Ast)).To(HaveSuffix(`FROM (SELECT column1 AS "id", column2 AS "name" FROM (VALUES (1, 'a'), (2, $1))) v`))
+		Expect(Validate(mgr, "sqlite")).To(MatchError(ContainSubstring("column lists for aliases are not supported")))
+	})
+
+	It("selects from table functions", func() {
+		series := NewTableAliasNode(TableFunction("generate_series", Sql(1), Sql(3)), "g", "n")
+		Expect(Select(series.Attr("n")).From(series).ToSql()).To(Equal(`SELECT g."n" FROM generate_series(1, 3) g("n")`))
+
+		posts := NewTable("posts")
+		tags := NewTableAliasNode(TableFunction("json_each", posts.Attr("tags")), "tags")
+		mgr := posts.Select(posts.Attr("id"), tags.Attr("value")).From(posts, tags)
+		Expect(mgr.ToSql()).To(Equal(`SELECT "posts"."id", tags."value" FROM "posts", json_each("posts"."tags") tags`))
+	})
+
+	It("selects from multiple sources and keeps the joins", func() {
+		users := NewTable("users")
+		teams := NewTable("teams")
+		posts := NewTable("posts")
+		mgr := users.Select(Star()).Join(posts).On(posts.Attr("user_id").Eq(users.Attr("id")))
+		mgr.From(users, teams)
+		Expect(mgr.ToSql()).To(Equal(`SELECT * FROM "users", "teams" INNER JOIN "posts" ON "posts"."user_id" = "users"."id"`))
+		mgr.From(users)
+		Expect(mgr.ToSql()).To(Equal(`SELECT * FROM "users" INNER JOIN "posts" ON "posts"."user_id" = "users"."id"`))
+	})
 })
@@ -17,5 +17,5 @@ func NewSelectStatementNode() *SelectStatementNode {
 }
 
 func (s *SelectStatementNode) IsEqual(s2 SelectStatementNode) bool {
-	return false
+	return StructurallyEqual(s, &s2)
 }
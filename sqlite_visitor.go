@@ -1,6 +1,7 @@
 package rel
 
 import (
+	"bytes"
 	"log"
 	"runtime/debug"
 )
@@ -8,6 +9,11 @@ import (
 // Used to handle generating Postgres specific sql
 type SQLiteVisitor struct {
 	Conn Connector
+	// Expand row value comparisons, which require SQLite 3.15
+	LegacyRowValues bool
+	// Render constructs missing from the dialect as written instead of
+	// applying Emulations, use Validate to report them as errors
+	DisableEmulation bool
 }
 
 func (v SQLiteVisitor) Accept(visitable Visitable) string {
@@ -15,6 +21,14 @@ func (v SQLiteVisitor) Accept(visitable Visitable) string {
 }
 
 func (v SQLiteVisitor) Visit(visitable Visitable) string {
+	if v.LegacyRowValues {
+		if expanded := expandRowValueComparison(visitable, false); expanded != nil {
+			return v.Visit(expanded)
+		}
+	}
+	if emulated := v.emulate(visitable); emulated != nil {
+		return v.Visit(emulated)
+	}
 	switch node := visitable.(type) {
 	case nil:
 		return visitationNil()
@@ -29,7 +43,13 @@ func (v SQLiteVisitor) Visit(visitable Visitable) string {
 	case *JoinSource:
 		return visitationJoinSourceNode(v, node)
 	case *EqualityNode:
-		return visitationEqualityNode(v, node)
+		return v.visitEqualityNode(node)
+	case *RollupNode:
+		return v.visitGroupingElement(node)
+	case *CubeNode:
+		return v.visitGroupingElement(node)
+	case *GroupingSetsNode:
+		return v.visitGroupingElement(node)
 	case *HavingNode:
 		return visitationHavingNode(v, node)
 	case *AttributeNode:
@@ -38,6 +58,10 @@ func (v SQLiteVisitor) Visit(visitable Visitable) string {
 		return visitationGroupNode(v, node)
 	case *ExistsNode:
 		return visitationExistsNode(v, node)
+	case *AnyNode:
+		return v.visitQuantifiedNode(node)
+	case *AllNode:
+		return v.visitQuantifiedNode(node)
 	case *AsNode:
 		return visitationAsNode(v, node)
 	case *LessThanNode:
@@ -68,6 +92,8 @@ func (v SQLiteVisitor) Visit(visitable Visitable) string {
 		return visitationTableAliasNode(v, node)
 	case *InnerJoinNode:
 		return visitationInnerJoinNode(v, node)
+	case *TupleNode:
+		return visitationTupleNode(v, node)
 	case *GroupingNode:
 		return visitationGroupingNode(v, node)
 	case *NamedWindowNode:
@@ -78,6 +104,8 @@ func (v SQLiteVisitor) Visit(visitable Visitable) string {
 		return visitationRowsNode(v, node)
 	case *LockNode:
 		return v.visitLockNode(node)
+	case *RowLockNode:
+		return v.visitRowLockNode(node)
 	case *PrecedingNode:
 		return visitationPrecedingNode(v, node)
 	case *FollowingNode:
@@ -106,13 +134,31 @@ func (v SQLiteVisitor) Visit(visitable Visitable) string {
 	case *SelectCoreNode:
 		return visitationSelectCoreNode(v, node)
 	case *NotEqualNode:
-		return visitationNotEqualNode(v, node)
+		return v.visitNotEqualNode(node)
+	case *IsDistinctFromNode:
+		return v.visitIsDistinctFromNode(node)
+	case *IsNotDistinctFromNode:
+		return v.visitIsNotDistinctFromNode(node)
 	case *NotNode:
 		return visitationNotNode(v, node)
 	case *GreaterThanOrEqualNode:
 		return visitationGreaterThanOrEqualNode(v, node)
 	case *LessThanOrEqualNode:
 		return visitationLessThanOrEqualNode(v, node)
+	case *CaseNode:
+		return visitationCaseNode(v, node)
+	case *WhenNode:
+		return visitationWhenNode(v, node)
+	case *StringAggNode:
+		return v.visitStringAggNode(node)
+	case *ArrayAggNode:
+		return v.visitArrayAggNode(node)
+	case *JsonAggNode:
+		return v.visitJsonAggNode(node)
+	case *BoolAndNode:
+		return v.visitBoolAndNode(node)
+	case *BoolOrNode:
+		return v.visitBoolOrNode(node)
 	case *OrNode:
 		return visitationOrNode(v, node)
 	case *AvgNode:
@@ -137,8 +183,14 @@ func (v SQLiteVisitor) Visit(visitable Visitable) string {
 		return visitationExtractNode(v, node)
 	case *InfixOperationNode:
 		return visitationInfixOperationNode(v, node)
+	case *BindParamNode:
+		return visitationBindParamNode(v, node)
 	case *QuotedNode:
 		return visitationQuotedNode(v, node)
+	case *ValueNode:
+		return visitationValueNode(v, node)
+	case attributeColumn:
+		return visitationAttributeNode(v, node.attributeNode())
 	case *OverNode:
 		return visitationOverNode(v, node)
 	case *AssignmentNode:
@@ -149,6 +201,14 @@ func (v SQLiteVisitor) Visit(visitable Visitable) string {
 		return visitationDistinctOnNode(v, node)
 	case *OuterJoinNode:
 		return visitationOuterJoinNode(v, node)
+	case *RightOuterJoinNode:
+		return visitationRightOuterJoinNode(v, node)
+	case *FullOuterJoinNode:
+		return visitationFullOuterJoinNode(v, node)
+	case *CommaJoinNode:
+		return visitationCommaJoinNode(v, node)
+	case *ValuesListNode:
+		return visitationValuesListNode(v, node, "")
 	case *OffsetNode:
 		return visitationOffsetNode(v, node)
 	case *LimitNode:
@@ -180,10 +240,20 @@ func (v SQLiteVisitor) QuoteTableName(visitable Visitable) string {
 			return alias.Name
 		}
 	}
+	if table, ok := visitable.(*Table); ok && table != nil {
+		return quoteQualifiedTableName(v.Conn, table)
+	}
 	return v.Conn.QuoteTableName(visitable.String())
 }
 
+// Quote writes booleans as 1 and 0, SQLite has no boolean type
 func (v SQLiteVisitor) Quote(thing interface{}) string {
+	if t, ok := thing.(bool); ok {
+		if t {
+			return "1"
+		}
+		return "0"
+	}
 	return v.Conn.Quote(thing)
 }
 
@@ -191,15 +261,127 @@ func (v SQLiteVisitor) QuoteColumnName(literal SqlLiteralNode) string {
 	return v.Conn.QuoteColumnName(literal.Raw)
 }
 
+func (v SQLiteVisitor) Capabilities() Capabilities {
+	return SQLiteCapabilities
+}
+
+func (v SQLiteVisitor) emulate(visitable Visitable) Visitable {
+	if v.DisableEmulation {
+		return nil
+	}
+	return emulate("sqlite", visitable)
+}
+
 // VisitLockNode is overwritten for the SQLiteVisitor
-// Locks are not supported in SQLite
+// Locks are not supported in SQLite, a transaction locks the whole
+// database. Validate reports them before the statement is rendered
 func (v *SQLiteVisitor) visitLockNode(node *LockNode) string {
+	log.Fatalf("SQLiteVisitor does not support %T", node)
+	return ""
+}
+
+func (v SQLiteVisitor) visitRowLockNode(node *RowLockNode) string {
+	log.Fatalf("SQLiteVisitor does not support %T", node)
 	return ""
 }
 
-func (v *SQLiteVisitor) visitSelectStatementNode(node *SelectStatementNode) string {
+func (v SQLiteVisitor) visitSelectStatementNode(node *SelectStatementNode) string {
+	return visitationSelectStatementNode(v, v.prepareStatement(node))
+}
+
+// SQLite requires a LIMIT before an OFFSET, a negative limit is none.
+// This is not an emulation, it is done when emulation is disabled
+func (v SQLiteVisitor) prepareStatement(node *SelectStatementNode) *SelectStatementNode {
 	if node.Offset != nil && node.Limit == nil {
-		node.Limit = &LimitNode{Expr: Sql("-1")}
+		limited := *node
+		limited.Limit = &LimitNode{Expr: Sql("-1")}
+		return &limited
+	}
+	return node
+}
+
+// SQLite's IS and IS NOT operators compare NULL values safely
+func (v SQLiteVisitor) visitIsDistinctFromNode(node *IsDistinctFromNode) string {
+	var buf bytes.Buffer
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(" IS NOT ")
+	buf.WriteString(v.Visit(node.Right))
+	return buf.String()
+}
+
+func (v SQLiteVisitor) visitIsNotDistinctFromNode(node *IsNotDistinctFromNode) string {
+	var buf bytes.Buffer
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(" IS ")
+	buf.WriteString(v.Visit(node.Right))
+	return buf.String()
+}
+
+// SQLite has no quantified comparisons, = ANY and != ALL
+// are rewritten to the equivalent IN and NOT IN
+func (v SQLiteVisitor) visitEqualityNode(node *EqualityNode) string {
+	if quantified, ok := node.Right.(*AnyNode); ok {
+		return v.Visit(&InNode{Left: node.Left, Right: []Visitable{quantified.Expr}})
+	}
+	return visitationEqualityNode(v, node)
+}
+
+func (v SQLiteVisitor) visitNotEqualNode(node *NotEqualNode) string {
+	if quantified, ok := node.Right.(*AllNode); ok {
+		return v.Visit(&NotInNode{Left: node.Left, Right: []Visitable{quantified.Expr}})
+	}
+	return visitationNotEqualNode(v, node)
+}
+
+func (v SQLiteVisitor) visitQuantifiedNode(node Visitable) string {
+	log.Fatalf("SQLiteVisitor only supports %T in = ANY and != ALL comparisons", node)
+	return ""
+}
+
+func (v SQLiteVisitor) visitGroupingElement(node Visitable) string {
+	log.Fatalf("SQLiteVisitor does not support %T", node)
+	return ""
+}
+
+// GROUP_CONCAT(DISTINCT x) takes no separator in SQLite and joins the
+// values with commas, so a distinct aggregate can only use a comma
+func (v SQLiteVisitor) visitStringAggNode(node *StringAggNode) string {
+	fn := (*FunctionNode)(node)
+	if fn.DistinctValues && len(fn.Expressions) > 1 {
+		if !isCommaSeparator(fn.Expressions[1]) {
+			log.Fatal("SQLiteVisitor only supports a comma separator in a distinct StringAgg")
+		}
+		distinct := *fn
+		distinct.Expressions = fn.Expressions[:1]
+		fn = &distinct
 	}
-	return visitationSelectStatementNode(v, node)
+	return visitationAggregate(v, "GROUP_CONCAT", fn)
+}
+
+func isCommaSeparator(separator Visitable) bool {
+	switch s := separator.(type) {
+	case *QuotedNode:
+		return s.Raw == ","
+	case *ValueNode:
+		return s.Value == ","
+	}
+	return false
+}
+
+func (v SQLiteVisitor) visitArrayAggNode(node *ArrayAggNode) string {
+	return visitationAggregate(v, "JSON_GROUP_ARRAY", (*FunctionNode)(node))
+}
+
+func (v SQLiteVisitor) visitJsonAggNode(node *JsonAggNode) string {
+	return visitationAggregate(v, "JSON_GROUP_ARRAY", (*FunctionNode)(node))
+}
+
+// booleans are stored as integers, MIN and MAX
+// behave like BOOL_AND and BOOL_OR
+func (v SQLiteVisitor) visitBoolAndNode(node *BoolAndNode) string {
+	return visitationAggregate(v, "MIN", (*FunctionNode)(node))
+}
+
+func (v SQLiteVisitor) visitBoolOrNode(node *BoolOrNode) string {
+	return visitationAggregate(v, "MAX", (*FunctionNode)(node))
 }
@@ -22,8 +22,48 @@ var _ = Describe("SQLiteVisitor", func() {
 
 	It("does not support locking", func() {
 		node := &LockNode{Expr: Sql("FOR UPDATE")}
-		sql := visitor.Accept(node)
-		Expect(sql).To(Equal(""))
+		Expect(Validate(node, "sqlite")).To(MatchError(ContainSubstring("locks are not supported")))
+		Expect(Validate(NewLockNode(NewRowLockNode(LockUpdate)), "sqlite")).To(MatchError(ContainSubstring("FOR UPDATE is not supported")))
 	})
 
+	It("uses IS NOT for is distinct from", func() {
+		users := NewTable("users")
+		node := users.Attr("a").IsDistinctFrom(users.Attr("b"))
+		Expect(visitor.Accept(node)).To(Equal(`"users"."a" IS NOT "users"."b"`))
+	})
+
+	It("uses IS for is not distinct from", func() {
+		users := NewTable("users")
+		node := users.Attr("a").IsNotDistinctFrom(users.Attr("b"))
+		Expect(visitor.Accept(node)).To(Equal(`"users"."a" IS "users"."b"`))
+	})
+
+	It("rewrites = ANY subqueries to IN", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		node := users.Attr("id").EqAnyQuery(posts.Select(posts.Attr("user_id")))
+		Expect(visitor.Accept(node)).To(Equal(`"users"."id" IN (SELECT "posts"."user_id" FROM "posts")`))
+	})
+
+	It("rewrites != ALL subqueries to NOT IN", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		node := users.Attr("id").NotEqAllQuery(posts.Select(posts.Attr("user_id")))
+		Expect(visitor.Accept(node)).To(Equal(`"users"."id" NOT IN (SELECT "posts"."user_id" FROM "posts")`))
+	})
+
+	It("renders a string aggregate as GROUP_CONCAT", func() {
+		users := NewTable("users")
+		node := StringAgg(users.Attr("name"), &QuotedNode{Raw: ","})
+		Expect(visitor.Accept(node)).To(Equal(`GROUP_CONCAT("users"."name", ',')`))
+		Expect(visitor.Accept(node.Distinct())).To(Equal(`GROUP_CONCAT(DISTINCT "users"."name")`))
+		Expect(Validate(node, "sqlite")).To(Succeed())
+		node = StringAgg(users.Attr("name"), &QuotedNode{Raw: ";"}).Distinct()
+		Expect(Validate(node, "sqlite")).To(MatchError(ContainSubstring("separators other than a comma")))
+	})
+
+	It("renders an array aggregate as JSON_GROUP_ARRAY", func() {
+		users := NewTable("users")
+		Expect(visitor.Accept(ArrayAgg(users.Attr("id")))).To(Equal(`JSON_GROUP_ARRAY("users"."id")`))
+	})
 })
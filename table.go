@@ -6,15 +6,42 @@ import (
 )
 
 type Table struct {
-	Name       string
+	Name string
+	// Schema and Database qualify the name, each is quoted separately.
+	// MySQL has no schemas within a database, set only one of them
+	Schema   string
+	Database string
+	// Columns declare the schema of the table, see WithColumns
+	Columns    []*ColumnDefinition
 	Engine     Engine
 	TableAlias string
 	Aliases    *[]*TableAliasNode
 	BaseVisitable
 }
 
-func NewTable(name string) *Table {
-	return &Table{Name: name, Engine: RelEngine}
+type TableOption func(*Table)
+
+// WithSchema qualifies the table name with a schema, as in "public"."users"
+func WithSchema(schema string) TableOption {
+	return func(t *Table) {
+		t.Schema = schema
+	}
+}
+
+// WithDatabase qualifies the table name with a database or catalog,
+// as in `shop`.`users` on MySQL or [shop].[dbo].[users] on SQL Server
+func WithDatabase(database string) TableOption {
+	return func(t *Table) {
+		t.Database = database
+	}
+}
+
+func NewTable(name string, options ...TableOption) *Table {
+	table := &Table{Name: name, Engine: RelEngine}
+	for _, option := range options {
+		option(table)
+	}
+	return table
 }
 
 func (t *Table) String() string {
@@ -4,9 +4,14 @@ type TableAliasNode struct {
 	Name     string
 	Quoted   bool      // Flag to indentify if the alias should be quoted
 	Relation Visitable // Generally a *Table, *GroupingNode; a GroupingNode can allow a SelectStatement to be aliased
+	Columns  []string  // Names for the columns of the relation, rendered as name(column, ...)
 	BinaryNode
 }
 
+func NewTableAliasNode(relation Visitable, name string, columns ...string) *TableAliasNode {
+	return &TableAliasNode{Relation: relation, Name: name, Columns: columns}
+}
+
 func (t *TableAliasNode) Attr(name string) *AttributeNode {
 	return NewAttributeNode(t, name)
 }
@@ -98,4 +98,27 @@ var _ = Describe("Table", func() {
 		sql := sm.ToSql()
 		Expect(sql).To(Equal(`SELECT`))
 	})
+
+	It("quotes the schema and database of a qualified table separately", func() {
+		users := NewTable("users", WithSchema("public"))
+		query := users.Select(users.Attr("id")).Where(users.Attr("age").Gt(Sql(21)))
+		Expect(query.ToSql()).To(Equal(`SELECT "public"."users"."id" FROM "public"."users" WHERE "public"."users"."age" > 21`))
+
+		orders := NewTable("orders", WithDatabase("shop"), WithSchema("dbo"))
+		Expect(orders.Select(Star()).ToSql()).To(Equal(`SELECT * FROM "shop"."dbo"."orders"`))
+
+		update := NewUpdateManager(RelEngine).Table(users).Set(users.Attr("name"), Sql("x"))
+		Expect(update.ToSql()).To(Equal(`UPDATE "public"."users" SET "name" = 'x'`))
+	})
+
+	It("references a qualified table by its alias", func() {
+		users := NewTable("users", WithSchema("public"))
+		users.SetTableAlias("u")
+		Expect(users.Select(users.Attr("id")).ToSql()).To(Equal(`SELECT "u"."id" FROM "public"."users" "u"`))
+
+		accounts := NewTable("accounts", WithDatabase("billing"))
+		alias := accounts.Alias()
+		mgr := Select(alias.Attr("id")).From(alias)
+		Expect(mgr.ToSql()).To(Equal(`SELECT "accounts_2"."id" FROM "billing"."accounts" "accounts_2"`))
+	})
 })
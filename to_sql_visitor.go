@@ -28,6 +28,12 @@ func (v *ToSqlVisitor) Visit(visitable Visitable) string {
 		return visitationJoinSourceNode(v, node)
 	case *EqualityNode:
 		return visitationEqualityNode(v, node)
+	case *RollupNode:
+		return visitationRollupNode(v, node)
+	case *CubeNode:
+		return visitationCubeNode(v, node)
+	case *GroupingSetsNode:
+		return visitationGroupingSetsNode(v, node)
 	case *HavingNode:
 		return visitationHavingNode(v, node)
 	case *AttributeNode:
@@ -36,6 +42,10 @@ func (v *ToSqlVisitor) Visit(visitable Visitable) string {
 		return visitationGroupNode(v, node)
 	case *ExistsNode:
 		return visitationExistsNode(v, node)
+	case *AnyNode:
+		return visitationAnyNode(v, node)
+	case *AllNode:
+		return visitationAllNode(v, node)
 	case *AsNode:
 		return visitationAsNode(v, node)
 	case *LessThanNode:
@@ -68,6 +78,8 @@ func (v *ToSqlVisitor) Visit(visitable Visitable) string {
 		return visitationTableAliasNode(v, node)
 	case *InnerJoinNode:
 		return visitationInnerJoinNode(v, node)
+	case *TupleNode:
+		return visitationTupleNode(v, node)
 	case *GroupingNode:
 		return visitationGroupingNode(v, node)
 	case *NamedWindowNode:
@@ -78,6 +90,8 @@ func (v *ToSqlVisitor) Visit(visitable Visitable) string {
 		return visitationRowsNode(v, node)
 	case *LockNode:
 		return visitationLockNode(v, node)
+	case *RowLockNode:
+		return visitationRowLockNode(v, node)
 	case *PrecedingNode:
 		return visitationPrecedingNode(v, node)
 	case *FollowingNode:
@@ -107,12 +121,30 @@ func (v *ToSqlVisitor) Visit(visitable Visitable) string {
 		return visitationSelectCoreNode(v, node)
 	case *NotEqualNode:
 		return visitationNotEqualNode(v, node)
+	case *IsDistinctFromNode:
+		return visitationIsDistinctFromNode(v, node)
+	case *IsNotDistinctFromNode:
+		return visitationIsNotDistinctFromNode(v, node)
 	case *NotNode:
 		return visitationNotNode(v, node)
 	case *GreaterThanOrEqualNode:
 		return visitationGreaterThanOrEqualNode(v, node)
 	case *LessThanOrEqualNode:
 		return visitationLessThanOrEqualNode(v, node)
+	case *CaseNode:
+		return visitationCaseNode(v, node)
+	case *WhenNode:
+		return visitationWhenNode(v, node)
+	case *StringAggNode:
+		return visitationStringAggNode(v, node)
+	case *ArrayAggNode:
+		return visitationArrayAggNode(v, node)
+	case *JsonAggNode:
+		return visitationJsonAggNode(v, node)
+	case *BoolAndNode:
+		return visitationBoolAndNode(v, node)
+	case *BoolOrNode:
+		return visitationBoolOrNode(v, node)
 	case *OrNode:
 		return visitationOrNode(v, node)
 	case *AvgNode:
@@ -137,8 +169,14 @@ func (v *ToSqlVisitor) Visit(visitable Visitable) string {
 		return visitationExtractNode(v, node)
 	case *InfixOperationNode:
 		return visitationInfixOperationNode(v, node)
+	case *BindParamNode:
+		return visitationBindParamNode(v, node)
 	case *QuotedNode:
 		return visitationQuotedNode(v, node)
+	case *ValueNode:
+		return visitationValueNode(v, node)
+	case attributeColumn:
+		return visitationAttributeNode(v, node.attributeNode())
 	case *OverNode:
 		return visitationOverNode(v, node)
 	case *AssignmentNode:
@@ -149,6 +187,14 @@ func (v *ToSqlVisitor) Visit(visitable Visitable) string {
 		return visitationDistinctOnNode(v, node)
 	case *OuterJoinNode:
 		return visitationOuterJoinNode(v, node)
+	case *RightOuterJoinNode:
+		return visitationRightOuterJoinNode(v, node)
+	case *FullOuterJoinNode:
+		return visitationFullOuterJoinNode(v, node)
+	case *CommaJoinNode:
+		return visitationCommaJoinNode(v, node)
+	case *ValuesListNode:
+		return visitationValuesListNode(v, node, "")
 	case *OffsetNode:
 		return visitationOffsetNode(v, node)
 	case *LimitNode:
@@ -180,6 +226,9 @@ func (v ToSqlVisitor) QuoteTableName(visitable Visitable) string {
 			return alias.Name
 		}
 	}
+	if table, ok := visitable.(*Table); ok && table != nil {
+		return quoteQualifiedTableName(v.Conn, table)
+	}
 	return v.Conn.QuoteTableName(visitable.String())
 }
 
@@ -190,3 +239,7 @@ func (v ToSqlVisitor) Quote(thing interface{}) string {
 func (v ToSqlVisitor) QuoteColumnName(literal SqlLiteralNode) string {
 	return v.Conn.QuoteColumnName(literal.Raw)
 }
+
+func (v *ToSqlVisitor) Capabilities() Capabilities {
+	return ToSqlCapabilities
+}
@@ -0,0 +1,82 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TupleNode", func() {
+	var users *Table
+	var key *TupleNode
+
+	BeforeEach(func() {
+		users = NewTable("users")
+		key = Tuple(users.Attr("a"), users.Attr("b"))
+	})
+
+	It("implements Predicator", func() {
+		// compile time test
+		var _ Predicator = &TupleNode{}
+	})
+
+	It("can compare row values for equality", func() {
+		mgr := users.Select(Star()).Where(key.Eq(Tuple(Sql(1), Sql(2))))
+		expected := `SELECT * FROM "users" WHERE ("users"."a", "users"."b") = (1, 2)`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can order row values", func() {
+		mgr := users.Select(Star()).Where(key.Gt(Tuple(Sql(1), Sql(2))))
+		expected := `SELECT * FROM "users" WHERE ("users"."a", "users"."b") > (1, 2)`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	It("can be used in an IN list", func() {
+		mgr := users.Select(Star()).Where(key.In([]Visitable{
+			Tuple(Sql(1), Sql(2)),
+			Tuple(Sql(3), Sql(4)),
+		}))
+		expected := `SELECT * FROM "users" WHERE ("users"."a", "users"."b") IN ((1, 2), (3, 4))`
+		Expect(mgr.ToSql()).To(Equal(expected))
+	})
+
+	Describe("without row value support", func() {
+		var visitor Visitor
+
+		BeforeEach(func() {
+			visitor = &SQLiteVisitor{Conn: DefaultConnector{}, LegacyRowValues: true}
+		})
+
+		It("expands equality", func() {
+			node := key.Eq(Tuple(Sql(1), Sql(2)))
+			Expect(visitor.Accept(node)).To(Equal(`("users"."a" = 1 AND "users"."b" = 2)`))
+		})
+
+		It("expands inequality", func() {
+			node := key.NotEq(Tuple(Sql(1), Sql(2)))
+			Expect(visitor.Accept(node)).To(Equal(`NOT ("users"."a" = 1 AND "users"."b" = 2)`))
+		})
+
+		It("expands ordering comparisons lexicographically", func() {
+			node := key.Gt(Tuple(Sql(1), Sql(2)))
+			Expect(visitor.Accept(node)).To(Equal(`("users"."a" > 1 OR "users"."a" = 1 AND "users"."b" > 2)`))
+		})
+
+		It("keeps the original operator for the last column", func() {
+			node := Tuple(users.Attr("a"), users.Attr("b"), users.Attr("c")).LtEq(Tuple(Sql(1), Sql(2), Sql(3)))
+			Expect(visitor.Accept(node)).To(Equal(`("users"."a" < 1 OR "users"."a" = 1 AND ("users"."b" < 2 OR "users"."b" = 2 AND "users"."c" <= 3))`))
+		})
+
+		It("expands IN lists", func() {
+			node := key.In([]Visitable{Tuple(Sql(1), Sql(2)), Tuple(Sql(3), Sql(4))})
+			Expect(visitor.Accept(node)).To(Equal(`(("users"."a" = 1 AND "users"."b" = 2) OR ("users"."a" = 3 AND "users"."b" = 4))`))
+		})
+
+		It("only expands ordering comparisons on MySQL", func() {
+			mysql := &MysqlVisitor{Conn: DefaultConnector{}, LegacyRowValues: true}
+			Expect(mysql.Accept(key.Eq(Tuple(Sql(1), Sql(2))))).To(Equal(`("users"."a", "users"."b") = (1, 2)`))
+			Expect(mysql.Accept(key.Gt(Tuple(Sql(1), Sql(2))))).To(Equal(`("users"."a" > 1 OR "users"."a" = 1 AND "users"."b" > 2)`))
+		})
+	})
+})
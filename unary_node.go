@@ -12,7 +12,6 @@ type NotNode UnaryNode
 type OffsetNode UnaryNode
 type OnNode UnaryNode
 type UsingNode UnaryNode
-type OrderingNode UnaryNode
 type TopNode UnaryNode
 type LockNode UnaryNode
 type DistinctOnNode UnaryNode
@@ -23,6 +22,8 @@ type RangeNode UnaryNode
 type CurrentRowNode UnaryNode
 type PrecedingNode UnaryNode
 type FollowingNode UnaryNode
+type AnyNode UnaryNode
+type AllNode UnaryNode
 
 func NewUnaryNode(visitable Visitable) *UnaryNode {
 	return &UnaryNode{Expr: visitable}
@@ -75,3 +76,11 @@ func NewWithNode(visitable Visitable) *WithNode {
 func NewWithRecursiveNode(visitable Visitable) *WithRecursiveNode {
 	return &WithRecursiveNode{Expr: visitable}
 }
+
+func NewAnyNode(visitable Visitable) *AnyNode {
+	return &AnyNode{Expr: visitable}
+}
+
+func NewAllNode(visitable Visitable) *AllNode {
+	return &AllNode{Expr: visitable}
+}
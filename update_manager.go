@@ -61,3 +61,8 @@ func (mgr *UpdateManager) Set(field *AttributeNode, value Visitable) *UpdateMana
 	})
 	return mgr
 }
+
+// Clone returns a deep copy, modifying it never affects mgr
+func (mgr *UpdateManager) Clone() *UpdateManager {
+	return Clone(mgr).(*UpdateManager)
+}
@@ -58,4 +58,15 @@ var _ = Describe("UpdateManager", func() {
 			Expect(mgr.ToSql()).To(Equal(`UPDATE "users" WHERE "users"."id" = 1`))
 		})
 	})
+
+	It("can be cloned without leaking conditions", func() {
+		table := NewTable("users")
+		base := NewUpdateManager(RelEngine)
+		base.Table(table)
+		base.Set(table.Attr("name"), Sql("x"))
+		branch := base.Clone()
+		branch.Where(table.Attr("id").Eq(Sql(1)))
+		Expect(base.ToSql()).To(Equal(`UPDATE "users" SET "name" = 'x'`))
+		Expect(branch.ToSql()).To(Equal(`UPDATE "users" SET "name" = 'x' WHERE "users"."id" = 1`))
+	})
 })
@@ -0,0 +1,50 @@
+package rel
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// ValueNode is a Go value quoted by the connector, unlike Sql it
+// never renders its value as raw SQL
+type ValueNode struct {
+	Value interface{}
+	BaseVisitable
+}
+
+// NewValueNode converts values of named types such as type ID int
+// to their underlying type so the connector can quote them
+func NewValueNode(value interface{}) *ValueNode {
+	return &ValueNode{Value: plainValue(value)}
+}
+
+func plainValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	switch value.(type) {
+	case time.Time, json.Number:
+		return value
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.String:
+		return v.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32:
+		return float32(v.Float())
+	case reflect.Float64:
+		return v.Float()
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes()
+		}
+	}
+	return value
+}
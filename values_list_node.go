@@ -0,0 +1,21 @@
+package rel
+
+// ValuesListNode is a VALUES list of rows which can be selected from
+// like a table, As names the relation and its columns
+type ValuesListNode struct {
+	Rows [][]interface{}
+	BaseVisitable
+}
+
+func NewValuesListNode(rows ...[]interface{}) *ValuesListNode {
+	return &ValuesListNode{Rows: rows}
+}
+
+func (node *ValuesListNode) Row(values ...interface{}) *ValuesListNode {
+	node.Rows = append(node.Rows, values)
+	return node
+}
+
+func (node *ValuesListNode) As(name string, columns ...string) *TableAliasNode {
+	return NewTableAliasNode(&GroupingNode{Expr: []Visitable{node}}, name, columns...)
+}
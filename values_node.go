@@ -1,7 +1,9 @@
 package rel
 
+// ValuesNode is the row of an INSERT, Rows are inserted after it
 type ValuesNode struct {
 	Values  []interface{}
 	Columns []*AttributeNode
+	Rows    [][]interface{}
 	BaseVisitable
 }
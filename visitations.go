@@ -3,6 +3,7 @@ package rel
 import (
 	"bytes"
 	"log"
+	"reflect"
 	"strings"
 )
 
@@ -43,7 +44,13 @@ func visitationAssignmentNode(v Visitor, node *AssignmentNode) string {
 	var buf bytes.Buffer
 	buf.WriteString(v.Visit(node.Left))
 	buf.WriteString(" = ")
-	buf.WriteString(v.Quote(node.Right))
+	// plain values are quoted, expressions are rendered as nodes
+	switch node.Right.(type) {
+	case nil, SqlLiteralNode, *SqlLiteralNode, *BindParamNode:
+		buf.WriteString(v.Quote(node.Right))
+	default:
+		buf.WriteString(v.Visit(node.Right))
+	}
 	return buf.String()
 }
 
@@ -59,10 +66,18 @@ func visitationOverNode(v Visitor, node *OverNode) string {
 	return buf.String()
 }
 
+func visitationBindParamNode(v Visitor, node *BindParamNode) string {
+	return node.Raw
+}
+
 func visitationQuotedNode(v Visitor, node *QuotedNode) string {
 	return strings.Join([]string{"'", node.Raw, "'"}, "")
 }
 
+func visitationValueNode(v Visitor, node *ValueNode) string {
+	return v.Quote(node.Value)
+}
+
 func visitationInfixOperationNode(v Visitor, node *InfixOperationNode) string {
 	var buf bytes.Buffer
 	buf.WriteString(v.Visit(node.Left))
@@ -139,15 +154,26 @@ func visitationMatchesNode(v Visitor, node *MatchesNode) string {
 }
 
 func visitationNamedFunctionNode(v Visitor, node *NamedFunctionNode) string {
+	return visitationAggregate(v, node.Name.Raw, &node.FunctionNode)
+}
+
+// visitationAggregate renders NAME([DISTINCT] expressions) [FILTER (WHERE ...)] [AS alias]
+func visitationAggregate(v Visitor, name string, node *FunctionNode) string {
 	var buf bytes.Buffer
-	buf.WriteString(node.Name.Raw)
+	buf.WriteString(name)
 	buf.WriteString("(")
-	if node.Distinct {
-		buf.WriteString("DISINCT ")
+	if node.DistinctValues {
+		buf.WriteString("DISTINCT ")
 	}
 	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
 	buf.WriteString(")")
 
+	if node.FilterExpr != nil {
+		buf.WriteString(" FILTER (WHERE ")
+		buf.WriteString(v.Visit(node.FilterExpr))
+		buf.WriteString(")")
+	}
+
 	if node.Alias != nil {
 		buf.WriteString(" AS ")
 		buf.WriteString(v.Visit(node.Alias))
@@ -155,67 +181,86 @@ func visitationNamedFunctionNode(v Visitor, node *NamedFunctionNode) string {
 	return buf.String()
 }
 
-func visitationSumNode(v Visitor, node *SumNode) string {
-	var buf bytes.Buffer
-	buf.WriteString("SUM(")
-	if node.Distinct {
-		buf.WriteString("DISINCT ")
+// filterAsCase moves the FILTER condition of an aggregate into CASE
+// expressions for databases without FILTER, COUNT(*) counts a constant.
+// Only aggregates ignoring NULL values can be filtered this way
+func filterAsCase(node *FunctionNode) *FunctionNode {
+	if node.FilterExpr == nil {
+		return node
 	}
-	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
-	buf.WriteString(")")
-
-	if node.Alias != nil {
-		buf.WriteString(" AS ")
-		buf.WriteString(v.Visit(node.Alias))
+	fn := *node
+	fn.FilterExpr = nil
+	fn.Expressions = make([]Visitable, len(node.Expressions))
+	for i, expr := range node.Expressions {
+		if literal, ok := expr.(SqlLiteralNode); ok && literal.Raw == "*" {
+			expr = Sql(1)
+		}
+		fn.Expressions[i] = Case().When(node.FilterExpr, expr)
 	}
-	return buf.String()
+	return &fn
 }
 
-func visitationAvgNode(v Visitor, node *AvgNode) string {
-	var buf bytes.Buffer
-	buf.WriteString("AVG(")
-	if node.Distinct {
-		buf.WriteString("DISINCT ")
-	}
-	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
-	buf.WriteString(")")
+func visitationSumNode(v Visitor, node *SumNode) string {
+	return visitationAggregate(v, "SUM", (*FunctionNode)(node))
+}
 
-	if node.Alias != nil {
-		buf.WriteString(" AS ")
-		buf.WriteString(v.Visit(node.Alias))
-	}
-	return buf.String()
+func visitationAvgNode(v Visitor, node *AvgNode) string {
+	return visitationAggregate(v, "AVG", (*FunctionNode)(node))
 }
 
 func visitationMinNode(v Visitor, node *MinNode) string {
+	return visitationAggregate(v, "MIN", (*FunctionNode)(node))
+}
+
+func visitationMaxNode(v Visitor, node *MaxNode) string {
+	return visitationAggregate(v, "MAX", (*FunctionNode)(node))
+}
+
+func visitationStringAggNode(v Visitor, node *StringAggNode) string {
+	return visitationAggregate(v, "STRING_AGG", (*FunctionNode)(node))
+}
+
+func visitationArrayAggNode(v Visitor, node *ArrayAggNode) string {
+	return visitationAggregate(v, "ARRAY_AGG", (*FunctionNode)(node))
+}
+
+func visitationJsonAggNode(v Visitor, node *JsonAggNode) string {
+	return visitationAggregate(v, "JSON_AGG", (*FunctionNode)(node))
+}
+
+func visitationBoolAndNode(v Visitor, node *BoolAndNode) string {
+	return visitationAggregate(v, "BOOL_AND", (*FunctionNode)(node))
+}
+
+func visitationBoolOrNode(v Visitor, node *BoolOrNode) string {
+	return visitationAggregate(v, "BOOL_OR", (*FunctionNode)(node))
+}
+
+func visitationCaseNode(v Visitor, node *CaseNode) string {
 	var buf bytes.Buffer
-	buf.WriteString("MIN(")
-	if node.Distinct {
-		buf.WriteString("DISINCT ")
+	buf.WriteString("CASE")
+	if node.Case != nil {
+		buf.WriteString(SPACE)
+		buf.WriteString(v.Visit(node.Case))
 	}
-	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
-	buf.WriteString(")")
-
-	if node.Alias != nil {
-		buf.WriteString(" AS ")
-		buf.WriteString(v.Visit(node.Alias))
+	for _, when := range node.Conditions {
+		buf.WriteString(SPACE)
+		buf.WriteString(v.Visit(when))
 	}
+	if node.Default != nil {
+		buf.WriteString(" ELSE ")
+		buf.WriteString(v.Visit(node.Default))
+	}
+	buf.WriteString(" END")
 	return buf.String()
 }
 
-func visitationMaxNode(v Visitor, node *MaxNode) string {
+func visitationWhenNode(v Visitor, node *WhenNode) string {
 	var buf bytes.Buffer
-	buf.WriteString("MAX(")
-	if node.Distinct {
-		buf.WriteString("DISINCT ")
-	}
-	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
-	buf.WriteString(")")
-
-	if node.Alias != nil {
-		buf.WriteString(" AS ")
-		buf.WriteString(v.Visit(node.Alias))
-	}
+	buf.WriteString("WHEN ")
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(" THEN ")
+	buf.WriteString(v.Visit(node.Right))
 	return buf.String()
 }
 
@@ -256,18 +301,67 @@ func visitationNotEqualNode(v Visitor, node *NotEqualNode) string {
 	return buf.String()
 }
 
+// IS DISTINCT FROM is expanded into plain comparisons for
+// databases that cannot compare NULL values safely
+func visitationIsDistinctFromNode(v Visitor, node *IsDistinctFromNode) string {
+	if node.Right == nil {
+		return v.Visit(&NotEqualNode{Left: node.Left})
+	}
+	return v.Visit(&NotNode{Expr: notDistinctExpansion(node.Left, node.Right)})
+}
+
+func visitationIsNotDistinctFromNode(v Visitor, node *IsNotDistinctFromNode) string {
+	if node.Right == nil {
+		return v.Visit(&EqualityNode{Left: node.Left})
+	}
+	return v.Visit(notDistinctExpansion(node.Left, node.Right))
+}
+
+// (l = r AND l IS NOT NULL AND r IS NOT NULL) OR (l IS NULL AND r IS NULL)
+func notDistinctExpansion(left Visitable, right Visitable) *GroupingNode {
+	return &GroupingNode{
+		Expr: []Visitable{
+			&OrNode{
+				Left: &GroupingNode{Expr: []Visitable{&AndNode{Children: &[]Visitable{
+					&EqualityNode{Left: left, Right: right},
+					&NotEqualNode{Left: left},
+					&NotEqualNode{Left: right},
+				}}}},
+				Right: &GroupingNode{Expr: []Visitable{&AndNode{Children: &[]Visitable{
+					&EqualityNode{Left: left},
+					&EqualityNode{Left: right},
+				}}}},
+			},
+		},
+	}
+}
+
 func visitationValuesNode(v Visitor, node *ValuesNode) string {
 	var buf bytes.Buffer
-	buf.WriteString("VALUES (")
-	rangevals := []string{}
-	for _, value := range node.Values {
-		rangevals = append(rangevals, v.Quote(value))
+	buf.WriteString("VALUES ")
+	rows := []string{}
+	for _, row := range append([][]interface{}{node.Values}, node.Rows...) {
+		rangevals := []string{}
+		for _, value := range row {
+			rangevals = append(rangevals, visitationInsertValue(v, value))
+		}
+		rows = append(rows, "("+strings.Join(rangevals, COMMA)+")")
 	}
-	buf.WriteString(strings.Join(rangevals, COMMA))
-	buf.WriteString(")")
+	buf.WriteString(strings.Join(rows, COMMA))
 	return buf.String()
 }
 
+// visitationInsertValue quotes values and SQL literals, other
+// nodes are expressions computing the value
+func visitationInsertValue(v Visitor, value interface{}) string {
+	switch node := value.(type) {
+	case SqlLiteralNode, *BindParamNode:
+	case Visitable:
+		return v.Visit(node)
+	}
+	return v.Quote(value)
+}
+
 func visitationTrueNode(v Visitor, node *TrueNode) string {
 	return "TRUE"
 }
@@ -297,6 +391,27 @@ func visitationDeleteStatementNode(v Visitor, node *DeleteStatementNode) string
 func visitationUpdateStatementNode(v Visitor, node *UpdateStatementNode) string {
 	var buf bytes.Buffer
 
+	wheres := updateWheres(node)
+
+	buf.WriteString("UPDATE ")
+	buf.WriteString(v.Visit(node.Relation))
+
+	if node.Values != nil && len(*node.Values) > 0 {
+		buf.WriteString(" SET ")
+		buf.WriteString(iterateVisitAndJoinOnComma(v, *node.Values))
+	}
+
+	if wheres != nil && len(wheres) > 0 {
+		buf.WriteString(WHERE)
+		buf.WriteString(iterateVisitAndJoinOn(v, wheres, AND))
+	}
+
+	return buf.String()
+}
+
+// updateWheres returns the conditions of an update, ordered or limited
+// updates select the keys of the affected rows in a subquery
+func updateWheres(node *UpdateStatementNode) []Visitable {
 	var wheres []Visitable
 
 	if (node.Orders == nil || len(*node.Orders) == 0) && node.Limit == nil {
@@ -330,20 +445,7 @@ func visitationUpdateStatementNode(v Visitor, node *UpdateStatementNode) string
 			Right: []Visitable{stmt},
 		})
 	}
-	buf.WriteString("UPDATE ")
-	buf.WriteString(v.Visit(node.Relation))
-
-	if node.Values != nil && len(*node.Values) > 0 {
-		buf.WriteString(" SET ")
-		buf.WriteString(iterateVisitAndJoinOnComma(v, *node.Values))
-	}
-
-	if wheres != nil && len(wheres) > 0 {
-		buf.WriteString(WHERE)
-		buf.WriteString(iterateVisitAndJoinOn(v, wheres, AND))
-	}
-
-	return buf.String()
+	return wheres
 }
 
 func visitationInsertStatementNode(v Visitor, node *InsertStatementNode) string {
@@ -388,8 +490,181 @@ func visitationWithRecursiveNode(v Visitor, node *WithRecursiveNode) string {
 }
 
 func visitationDistinctOnNode(v Visitor, node *DistinctOnNode) string {
-	log.Fatal("NOT IMPLEMENTED FOR THIS DB")
-	return ""
+	var buf bytes.Buffer
+	buf.WriteString("DISTINCT ON ( ")
+	if tuple, ok := node.Expr.(*TupleNode); ok {
+		buf.WriteString(iterateVisitAndJoinOnComma(v, tuple.Expr))
+	} else {
+		buf.WriteString(v.Visit(node.Expr))
+	}
+	buf.WriteString(" )")
+	return buf.String()
+}
+
+const distinctOnRowNumber = "rel_row_number"
+
+// DISTINCT ON is emulated for databases without it by numbering the rows
+// of each group in a derived table and keeping the first row of each group.
+// When the projections cannot be listed, as for * of a table without
+// declared columns or unnamed expressions, every column of the derived
+// table is selected, including rel_row_number. * over joined tables could
+// name a column twice in the derived table and cannot be emulated.
+// Returns nil when the statement does not use DISTINCT ON
+func rewriteDistinctOn(node *SelectStatementNode) *SelectStatementNode {
+	if len(node.Cores) != 1 {
+		return nil
+	}
+	distinctOn, ok := node.Cores[0].SetQuantifier.(*DistinctOnNode)
+	if !ok {
+		return nil
+	}
+
+	partitions := []Visitable{distinctOn.Expr}
+	if tuple, ok := distinctOn.Expr.(*TupleNode); ok {
+		partitions = tuple.Expr
+	}
+	window := (&WindowNode{}).Partition(partitions...)
+	window.Orders = node.Orders
+
+	core := node.Cores[0]
+	if core.Selections == nil || len(*core.Selections) == 0 {
+		log.Fatal("DISTINCT ON cannot be emulated without projections")
+	}
+	projections, ok := derivedColumns(core)
+	if !ok {
+		if core.Source != nil && len(core.Source.Right) > 0 && selectsStar(core) {
+			log.Fatal("DISTINCT ON cannot be emulated for * over joined tables, list the columns")
+		}
+		projections = []Visitable{Star()}
+	}
+
+	rowNumber := Sql("ROW_NUMBER")
+	inner := core.copy()
+	inner.SetQuantifier = nil
+	*inner.Selections = append(*inner.Selections, &AsNode{
+		Left: &OverNode{
+			Left:  &NamedFunctionNode{Name: &rowNumber},
+			Right: window,
+		},
+		Right: Sql(distinctOnRowNumber),
+	})
+
+	outer := NewSelectStatementNode()
+	outer.With = node.With
+	outer.Cores[0].SetFrom(&TableAliasNode{
+		Relation: &GroupingNode{Expr: []Visitable{&SelectStatementNode{Cores: []*SelectCoreNode{inner}}}},
+		Name:     "subquery",
+	})
+	outer.Cores[0].Selections = &projections
+	outer.Cores[0].Wheres = &[]Visitable{&EqualityNode{Left: Sql(distinctOnRowNumber), Right: Sql(1)}}
+	// the orders must start with the DISTINCT ON expressions, which are
+	// unique in the result so any further orders have no effect
+	if node.Orders != nil {
+		orders := []Visitable{}
+		for _, order := range *node.Orders {
+			var expr Visitable
+			switch o := order.(type) {
+			case *AscendingNode:
+				expr, order = o.Expr, &AscendingNode{Expr: derivedColumn(o.Expr), Nulls: o.Nulls}
+			case *DescendingNode:
+				expr, order = o.Expr, &DescendingNode{Expr: derivedColumn(o.Expr), Nulls: o.Nulls}
+			default:
+				expr, order = o, derivedColumn(o)
+			}
+			if !containsVisitable(partitions, expr) {
+				break
+			}
+			orders = append(orders, order)
+		}
+		if len(orders) > 0 {
+			outer.Orders = &orders
+		}
+	}
+	outer.Limit = node.Limit
+	outer.Offset = node.Offset
+	return outer
+}
+
+// derivedColumns references the projections of a derived table from the
+// outer statement, * is listed from the declared columns of the table.
+// False is returned when a projection cannot be referenced
+func derivedColumns(core *SelectCoreNode) ([]Visitable, bool) {
+	if core.Selections == nil || len(*core.Selections) == 0 {
+		return nil, false
+	}
+	columns := []Visitable{}
+	for _, selection := range *core.Selections {
+		switch s := selection.(type) {
+		case *AttributeNode:
+			if s.Name.Raw != "*" {
+				columns = append(columns, derivedColumn(s))
+				continue
+			}
+			declared, ok := declaredColumns(s.Relation)
+			if !ok {
+				return nil, false
+			}
+			columns = append(columns, declared...)
+		case *AsNode:
+			columns = append(columns, s.Right)
+		case SqlLiteralNode:
+			if s.Raw != "*" || core.Source == nil || len(core.Source.Right) > 0 {
+				return nil, false
+			}
+			declared, ok := declaredColumns(core.Source.Left)
+			if !ok {
+				return nil, false
+			}
+			columns = append(columns, declared...)
+		default:
+			return nil, false
+		}
+	}
+	return columns, true
+}
+
+func selectsStar(core *SelectCoreNode) bool {
+	for _, selection := range *core.Selections {
+		switch s := selection.(type) {
+		case *AttributeNode:
+			if s.Name.Raw == "*" {
+				return true
+			}
+		case SqlLiteralNode:
+			if s.Raw == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func declaredColumns(relation Visitable) ([]Visitable, bool) {
+	t := declaredTable(relation)
+	if t == nil {
+		return nil, false
+	}
+	columns := []Visitable{}
+	for _, column := range t.Columns {
+		columns = append(columns, &UnqualifiedColumnNode{Expr: NewAttributeNode(relation, column.Name)})
+	}
+	return columns, true
+}
+
+func containsVisitable(visitables []Visitable, visitable Visitable) bool {
+	for _, v := range visitables {
+		if reflect.DeepEqual(v, visitable) {
+			return true
+		}
+	}
+	return false
+}
+
+func derivedColumn(visitable Visitable) Visitable {
+	if attr, ok := visitable.(*AttributeNode); ok {
+		return &UnqualifiedColumnNode{Expr: attr}
+	}
+	return visitable
 }
 
 func visitationDistinctNode(v Visitor, node *DistinctNode) string {
@@ -454,21 +729,7 @@ func visitationNamedWindowNode(v Visitor, node *NamedWindowNode) string {
 	var buf bytes.Buffer
 	buf.WriteString(v.QuoteColumnName(node.Name))
 	buf.WriteString(" AS (")
-	visitOrders := (node.Orders != nil && len(*node.Orders) > 0)
-	visitFraming := (node.Framing != nil)
-	if visitOrders {
-		buf.WriteString("ORDER BY ")
-		buf.WriteString(iterateVisitAndJoinOnComma(v, *node.Orders))
-	}
-
-	if visitOrders && visitFraming {
-		buf.WriteString(SPACE)
-	}
-
-	if visitFraming {
-		buf.WriteString(v.Visit(node.Framing))
-	}
-
+	buf.WriteString(visitWindowDefinition(v, node.Partitions, node.Orders, node.Framing))
 	buf.WriteString(")")
 	return buf.String()
 }
@@ -476,26 +737,51 @@ func visitationNamedWindowNode(v Visitor, node *NamedWindowNode) string {
 func visitationWindowNode(v Visitor, node *WindowNode) string {
 	var buf bytes.Buffer
 	buf.WriteString("(")
-	visitOrders := (node.Orders != nil && len(*node.Orders) > 0)
-	visitFraming := (node.Framing != nil)
-	if visitOrders {
-		buf.WriteString("ORDER BY ")
-		buf.WriteString(iterateVisitAndJoinOnComma(v, *node.Orders))
-	}
+	buf.WriteString(visitWindowDefinition(v, node.Partitions, node.Orders, node.Framing))
+	buf.WriteString(")")
+	return buf.String()
+}
 
-	if visitOrders && visitFraming {
-		buf.WriteString(SPACE)
+func visitWindowDefinition(v Visitor, partitions *[]Visitable, orders *[]Visitable, framing Visitable) string {
+	clauses := []string{}
+	if partitions != nil && len(*partitions) > 0 {
+		clauses = append(clauses, "PARTITION BY "+iterateVisitAndJoinOnComma(v, *partitions))
 	}
-
-	if visitFraming {
-		buf.WriteString(v.Visit(node.Framing))
+	if orders != nil && len(*orders) > 0 {
+		clauses = append(clauses, "ORDER BY "+iterateVisitAndJoinOnComma(v, *orders))
+	}
+	if framing != nil {
+		clauses = append(clauses, v.Visit(framing))
 	}
+	return strings.Join(clauses, SPACE)
+}
 
+// visitationGroupingNode renders a grouped set operation, which is
+// already parenthesized, as (SELECT ... UNION SELECT ...) rather than
+// with a second pair of parentheses
+func visitationGroupingNode(v Visitor, node *GroupingNode) string {
+	if len(node.Expr) == 1 && isSetOperation(node.Expr[0]) {
+		sql := v.Visit(node.Expr[0])
+		if strings.HasPrefix(sql, "( ") && strings.HasSuffix(sql, " )") {
+			return "(" + sql[2:len(sql)-2] + ")"
+		}
+	}
+	var buf bytes.Buffer
+	buf.WriteString("(")
+	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expr))
 	buf.WriteString(")")
 	return buf.String()
 }
 
-func visitationGroupingNode(v Visitor, node *GroupingNode) string {
+func isSetOperation(node Visitable) bool {
+	switch node.(type) {
+	case *UnionNode, *UnionAllNode, *IntersectNode, *ExceptNode:
+		return true
+	}
+	return false
+}
+
+func visitationTupleNode(v Visitor, node *TupleNode) string {
 	var buf bytes.Buffer
 	buf.WriteString("(")
 	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expr))
@@ -503,6 +789,112 @@ func visitationGroupingNode(v Visitor, node *GroupingNode) string {
 	return buf.String()
 }
 
+// Row value comparisons are rewritten into comparisons of the individual
+// columns for databases that lack support for them. Returns nil if the
+// node is not a comparison between two tuples of the same length
+func expandRowValueComparison(visitable Visitable, orderingOnly bool) Visitable {
+	lt := func(l, r Visitable) Visitable { return &LessThanNode{Left: l, Right: r} }
+	lteq := func(l, r Visitable) Visitable { return &LessThanOrEqualNode{Left: l, Right: r} }
+	gt := func(l, r Visitable) Visitable { return &GreaterThanNode{Left: l, Right: r} }
+	gteq := func(l, r Visitable) Visitable { return &GreaterThanOrEqualNode{Left: l, Right: r} }
+
+	switch node := visitable.(type) {
+	case *LessThanNode:
+		if left, right, ok := tuplePair(node.Left, node.Right); ok {
+			return expandTupleOrdering(left, right, lt, lt)
+		}
+	case *LessThanOrEqualNode:
+		if left, right, ok := tuplePair(node.Left, node.Right); ok {
+			return expandTupleOrdering(left, right, lt, lteq)
+		}
+	case *GreaterThanNode:
+		if left, right, ok := tuplePair(node.Left, node.Right); ok {
+			return expandTupleOrdering(left, right, gt, gt)
+		}
+	case *GreaterThanOrEqualNode:
+		if left, right, ok := tuplePair(node.Left, node.Right); ok {
+			return expandTupleOrdering(left, right, gt, gteq)
+		}
+	}
+
+	if orderingOnly {
+		return nil
+	}
+
+	switch node := visitable.(type) {
+	case *EqualityNode:
+		if left, right, ok := tuplePair(node.Left, node.Right); ok {
+			return expandTupleEquality(left, right)
+		}
+	case *NotEqualNode:
+		if left, right, ok := tuplePair(node.Left, node.Right); ok {
+			return &NotNode{Expr: expandTupleEquality(left, right)}
+		}
+	case *InNode:
+		if alternatives, ok := expandTupleList(node.Left, node.Right); ok {
+			return predicationGroupAny(nil, alternatives...)
+		}
+	case *NotInNode:
+		if alternatives, ok := expandTupleList(node.Left, node.Right); ok {
+			return &NotNode{Expr: predicationGroupAny(nil, alternatives...)}
+		}
+	}
+	return nil
+}
+
+func tuplePair(left Visitable, right Visitable) (*TupleNode, *TupleNode, bool) {
+	l, ok := left.(*TupleNode)
+	if !ok {
+		return nil, nil, false
+	}
+	r, ok := right.(*TupleNode)
+	if !ok || len(l.Expr) != len(r.Expr) || len(l.Expr) == 0 {
+		return nil, nil, false
+	}
+	return l, r, true
+}
+
+// (a, b) = (x, y) becomes (a = x AND b = y)
+func expandTupleEquality(left *TupleNode, right *TupleNode) *GroupingNode {
+	children := make([]Visitable, len(left.Expr))
+	for i := range left.Expr {
+		children[i] = &EqualityNode{Left: left.Expr[i], Right: right.Expr[i]}
+	}
+	return &GroupingNode{Expr: []Visitable{&AndNode{Children: &children}}}
+}
+
+// (a, b) > (x, y) becomes (a > x OR (a = x AND b > y)), the last
+// column is compared with the original operator
+func expandTupleOrdering(left *TupleNode, right *TupleNode, strict func(Visitable, Visitable) Visitable, last func(Visitable, Visitable) Visitable) Visitable {
+	n := len(left.Expr) - 1
+	memo := last(left.Expr[n], right.Expr[n])
+	for i := n - 1; i >= 0; i-- {
+		memo = &GroupingNode{Expr: []Visitable{&OrNode{
+			Left: strict(left.Expr[i], right.Expr[i]),
+			Right: &AndNode{Children: &[]Visitable{
+				&EqualityNode{Left: left.Expr[i], Right: right.Expr[i]},
+				memo,
+			}},
+		}}}
+	}
+	return memo
+}
+
+func expandTupleList(left Visitable, right []Visitable) ([]Visitable, bool) {
+	if len(right) == 0 {
+		return nil, false
+	}
+	alternatives := make([]Visitable, len(right))
+	for i, r := range right {
+		l, t, ok := tuplePair(left, r)
+		if !ok {
+			return nil, false
+		}
+		alternatives[i] = expandTupleEquality(l, t)
+	}
+	return alternatives, true
+}
+
 func visitationLimitNode(v Visitor, node *LimitNode) string {
 	var buf bytes.Buffer
 	buf.WriteString("LIMIT ")
@@ -514,6 +906,41 @@ func visitationLockNode(v Visitor, node *LockNode) string {
 	return v.Visit(node.Expr)
 }
 
+func visitationRowLockNode(v Visitor, node *RowLockNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("FOR ")
+	buf.WriteString(node.Strength.String())
+	if len(node.Tables) > 0 {
+		buf.WriteString(" OF ")
+		buf.WriteString(visitLockedTables(v, node.Tables))
+	}
+	if node.Wait != LockWaitDefault {
+		buf.WriteString(SPACE)
+		buf.WriteString(node.Wait.String())
+	}
+	return buf.String()
+}
+
+// tables in a lock clause are referenced by their alias when they have one
+func visitLockedTables(v Visitor, visitables []Visitable) string {
+	rangevals := []string{}
+	for _, visitable := range visitables {
+		switch t := visitable.(type) {
+		case *Table:
+			if t.TableAlias != "" {
+				rangevals = append(rangevals, v.QuoteTableName(&TableAliasNode{Relation: t, Name: t.TableAlias, Quoted: true}))
+			} else {
+				rangevals = append(rangevals, v.QuoteTableName(t))
+			}
+		case *TableAliasNode:
+			rangevals = append(rangevals, v.QuoteTableName(t))
+		default:
+			rangevals = append(rangevals, v.Visit(t))
+		}
+	}
+	return strings.Join(rangevals, COMMA)
+}
+
 func visitationOffsetNode(v Visitor, node *OffsetNode) string {
 	var buf bytes.Buffer
 	buf.WriteString("OFFSET ")
@@ -530,25 +957,17 @@ func visitationAndNode(v Visitor, node *AndNode) string {
 }
 
 func visitationCountNode(v Visitor, node *CountNode) string {
-	var buf bytes.Buffer
-	buf.WriteString("COUNT(")
-	if node.Distinct {
-		buf.WriteString("DISINCT ")
-	}
-	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
-	buf.WriteString(")")
-
-	if node.Alias != nil {
-		buf.WriteString(" AS ")
-		buf.WriteString(v.Visit(node.Alias))
-	}
-	return buf.String()
+	return visitationAggregate(v, "COUNT", (*FunctionNode)(node))
 }
 
 func visitationAscendingNode(v Visitor, node *AscendingNode) string {
 	var buf bytes.Buffer
 	buf.WriteString(v.Visit(node.Expr))
 	buf.WriteString(" ASC")
+	if node.Nulls != NullsDefault {
+		buf.WriteString(SPACE)
+		buf.WriteString(node.Nulls.String())
+	}
 	return buf.String()
 }
 
@@ -556,6 +975,10 @@ func visitationDescendingNode(v Visitor, node *DescendingNode) string {
 	var buf bytes.Buffer
 	buf.WriteString(v.Visit(node.Expr))
 	buf.WriteString(" DESC")
+	if node.Nulls != NullsDefault {
+		buf.WriteString(SPACE)
+		buf.WriteString(node.Nulls.String())
+	}
 	return buf.String()
 }
 
@@ -648,6 +1071,46 @@ func visitationGroupNode(v Visitor, node *GroupNode) string {
 	return v.Visit(node.Expr)
 }
 
+func visitationAnyNode(v Visitor, node *AnyNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("ANY (")
+	buf.WriteString(v.Visit(node.Expr))
+	buf.WriteString(")")
+	return buf.String()
+}
+
+func visitationAllNode(v Visitor, node *AllNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("ALL (")
+	buf.WriteString(v.Visit(node.Expr))
+	buf.WriteString(")")
+	return buf.String()
+}
+
+func visitationRollupNode(v Visitor, node *RollupNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("ROLLUP (")
+	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
+	buf.WriteString(")")
+	return buf.String()
+}
+
+func visitationCubeNode(v Visitor, node *CubeNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("CUBE (")
+	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
+	buf.WriteString(")")
+	return buf.String()
+}
+
+func visitationGroupingSetsNode(v Visitor, node *GroupingSetsNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("GROUPING SETS (")
+	buf.WriteString(iterateVisitAndJoinOnComma(v, node.Expressions))
+	buf.WriteString(")")
+	return buf.String()
+}
+
 func visitationHavingNode(v Visitor, node *HavingNode) string {
 	var buf bytes.Buffer
 	buf.WriteString("HAVING ")
@@ -669,8 +1132,15 @@ func visitationExistsNode(v Visitor, node *ExistsNode) string {
 }
 
 func visitationAttributeNode(v Visitor, node *AttributeNode) string {
+	mustKnowColumn(node)
 	var buf bytes.Buffer
-	buf.WriteString(v.QuoteTableName(node.Relation))
+	relation := node.Relation
+	// qualify with the table alias so correlated subqueries
+	// can reference an aliased outer table
+	if t, ok := relation.(*Table); ok && t.TableAlias != "" {
+		relation = &TableAliasNode{Relation: t, Name: t.TableAlias, Quoted: true}
+	}
+	buf.WriteString(v.QuoteTableName(relation))
 	buf.WriteString(".")
 	buf.WriteString(v.QuoteColumnName(node.Name))
 	return buf.String()
@@ -689,6 +1159,18 @@ func visitationEqualityNode(v Visitor, node *EqualityNode) string {
 	return buf.String()
 }
 
+// quoteQualifiedTableName quotes the database, schema and
+// name of a table as separate identifiers
+func quoteQualifiedTableName(conn Connector, table *Table) string {
+	parts := []string{}
+	for _, qualifier := range []string{table.Database, table.Schema} {
+		if qualifier != "" {
+			parts = append(parts, conn.QuoteTableName(qualifier))
+		}
+	}
+	return strings.Join(append(parts, conn.QuoteTableName(table.Name)), ".")
+}
+
 func visitationTable(v Visitor, table *Table) string {
 	var buf bytes.Buffer
 	buf.WriteString(v.QuoteTableName(table))
@@ -708,13 +1190,40 @@ func visitationJoinSourceNode(v Visitor, node *JoinSource) string {
 	if node.Left != nil {
 		buf.WriteString(v.Visit(node.Left))
 	}
-	buf.WriteString(iterateVisitAndJoinOn(v, node.Right, SPACE))
+	// joins are separated by a space, listed sources by their comma
+	listed := true
+	for _, join := range node.Right {
+		_, comma := join.(*CommaJoinNode)
+		if !comma && !listed {
+			buf.WriteString(SPACE)
+		}
+		buf.WriteString(v.Visit(join))
+		listed = comma
+	}
 	return buf.String()
 }
 
 func visitationOuterJoinNode(v Visitor, node *OuterJoinNode) string {
 	var buf bytes.Buffer
-	buf.WriteString("LEFT OUTER JOIN ")
+	buf.WriteString(" LEFT OUTER JOIN ")
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(SPACE)
+	buf.WriteString(v.Visit(node.Right))
+	return buf.String()
+}
+
+func visitationRightOuterJoinNode(v Visitor, node *RightOuterJoinNode) string {
+	var buf bytes.Buffer
+	buf.WriteString(" RIGHT OUTER JOIN ")
+	buf.WriteString(v.Visit(node.Left))
+	buf.WriteString(SPACE)
+	buf.WriteString(v.Visit(node.Right))
+	return buf.String()
+}
+
+func visitationFullOuterJoinNode(v Visitor, node *FullOuterJoinNode) string {
+	var buf bytes.Buffer
+	buf.WriteString(" FULL OUTER JOIN ")
 	buf.WriteString(v.Visit(node.Left))
 	buf.WriteString(SPACE)
 	buf.WriteString(v.Visit(node.Right))
@@ -741,6 +1250,44 @@ func visitationTableAliasNode(v Visitor, node *TableAliasNode) string {
 	buf.WriteString(v.Visit(node.Relation))
 	buf.WriteString(" ")
 	buf.WriteString(v.QuoteTableName(node))
+	buf.WriteString(visitationAliasColumns(v, node.Columns))
+	return buf.String()
+}
+
+// visitationAliasColumns renders the column list of an alias
+func visitationAliasColumns(v Visitor, columns []string) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	quoted := []string{}
+	for _, column := range columns {
+		quoted = append(quoted, v.QuoteColumnName(Sql(column)))
+	}
+	return "(" + strings.Join(quoted, COMMA) + ")"
+}
+
+func visitationCommaJoinNode(v Visitor, node *CommaJoinNode) string {
+	return ", " + v.Visit(node.Left)
+}
+
+// visitationValuesListNode renders VALUES (a, b), (c, d), every row
+// is prefixed with rowPrefix
+func visitationValuesListNode(v Visitor, node *ValuesListNode, rowPrefix string) string {
+	var buf bytes.Buffer
+	buf.WriteString("VALUES ")
+	rows := []string{}
+	for _, row := range node.Rows {
+		values := []string{}
+		for _, value := range row {
+			if visitable, ok := value.(Visitable); ok {
+				values = append(values, v.Visit(visitable))
+			} else {
+				values = append(values, v.Quote(value))
+			}
+		}
+		rows = append(rows, rowPrefix+"("+strings.Join(values, COMMA)+")")
+	}
+	buf.WriteString(strings.Join(rows, COMMA))
 	return buf.String()
 }
 
@@ -768,8 +1315,8 @@ func visitationSelectCoreNode(v Visitor, node *SelectCoreNode) string {
 
 	// add FROM statement to the buffer
 	if node.Source != nil && node.Source.Left != nil {
-		// assert the source is a *Table and check the length of the name
-		if t, ok := node.Source.Left.(*Table); ok && t.Name != "" {
+		// a *Table source is only emitted when it has a name
+		if t, ok := node.Source.Left.(*Table); !ok || (t != nil && t.Name != "") {
 			buf.WriteString(" FROM ")
 			buf.WriteString(v.Visit(node.Source))
 		}
@@ -778,7 +1325,7 @@ func visitationSelectCoreNode(v Visitor, node *SelectCoreNode) string {
 	// add WHERE statement to the buffer
 	if node.Wheres != nil && len(*node.Wheres) > 0 {
 		buf.WriteString(WHERE)
-		buf.WriteString(iterateVisitAndJoinOnComma(v, *node.Wheres))
+		buf.WriteString(iterateVisitAndJoinOn(v, *node.Wheres, AND))
 	}
 
 	// add GROUP BY statement to the buffer
@@ -0,0 +1,155 @@
+package rel
+
+import (
+	"log"
+	"reflect"
+)
+
+var (
+	visitableType = reflect.TypeOf((*Visitable)(nil)).Elem()
+	tableType     = reflect.TypeOf(Table{})
+)
+
+// Walk calls fn for node and every node below it, depth first in the
+// order of struct fields. Children are skipped when fn returns false,
+// tables are leaves.
+func Walk(node Visitable, fn func(Visitable) bool) {
+	if node == nil {
+		return
+	}
+	t := traversal{enter: fn, seen: make(map[clonedPointer]reflect.Value)}
+	t.node(reflect.ValueOf(node))
+}
+
+// Rewrite returns a copy of node where every node has been replaced by
+// the result of fn, children are rewritten before their parents.
+// The original tree is never modified.
+func Rewrite(node Visitable, fn func(Visitable) Visitable) Visitable {
+	if node == nil {
+		return nil
+	}
+	t := traversal{leave: fn, seen: make(map[clonedPointer]reflect.Value)}
+	return valueToVisitable(t.node(reflect.ValueOf(Clone(node))))
+}
+
+type traversal struct {
+	enter func(Visitable) bool
+	leave func(Visitable) Visitable
+	seen  map[clonedPointer]reflect.Value
+}
+
+// node visits a value holding a Visitable and returns its replacement
+func (t traversal) node(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return v
+		}
+		key := clonedPointer{v.Pointer(), v.Type()}
+		if replaced, ok := t.seen[key]; ok {
+			return replaced
+		}
+		t.seen[key] = v
+	}
+	visitable := v.Interface().(Visitable)
+	if t.enter != nil && !t.enter(visitable) {
+		return v
+	}
+
+	if t.isBranch(v.Type()) {
+		if v.Kind() == reflect.Ptr {
+			t.children(v.Elem())
+		} else {
+			copied := reflect.New(v.Type()).Elem()
+			copied.Set(v)
+			t.children(copied)
+			v = copied
+			visitable = v.Interface().(Visitable)
+		}
+	}
+
+	if t.leave == nil {
+		return v
+	}
+	replaced := reflect.ValueOf(t.leave(visitable))
+	if v.Kind() == reflect.Ptr {
+		t.seen[clonedPointer{v.Pointer(), v.Type()}] = replaced
+	}
+	return replaced
+}
+
+func (t traversal) isBranch(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && typ != tableType
+}
+
+// children visits every field of an addressable node struct,
+// embedded structs such as FunctionNode are part of their parent
+func (t traversal) children(s reflect.Value) {
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Field(i)
+		info := s.Type().Field(i)
+		if !field.CanSet() || info.Type == engineType {
+			continue
+		}
+		if info.Anonymous && field.Kind() == reflect.Struct {
+			t.children(field)
+			continue
+		}
+		t.value(field)
+	}
+}
+
+// value visits a settable field or slice element which may hold nodes
+func (t traversal) value(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || !v.Elem().Type().Implements(visitableType) {
+			return
+		}
+		t.replace(v, t.node(v.Elem()))
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if v.Type().Implements(visitableType) && v.Elem().Kind() == reflect.Struct {
+			t.replace(v, t.node(v))
+		} else {
+			t.value(v.Elem())
+		}
+	case reflect.Struct:
+		if v.Type().Implements(visitableType) {
+			t.replace(v, t.node(v))
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			t.value(v.Index(i))
+		}
+	}
+}
+
+func (t traversal) replace(slot reflect.Value, replaced reflect.Value) {
+	if t.leave == nil {
+		return
+	}
+	if !replaced.IsValid() {
+		// nil results are only accepted where a node is optional
+		if slot.Kind() != reflect.Interface && slot.Kind() != reflect.Ptr {
+			log.Fatalf("Rewrite cannot remove a %s", slot.Type())
+		}
+		slot.Set(reflect.Zero(slot.Type()))
+		return
+	}
+	if !replaced.Type().AssignableTo(slot.Type()) {
+		log.Fatalf("Rewrite cannot replace %s with %s", slot.Type(), replaced.Type())
+	}
+	slot.Set(replaced)
+}
+
+func valueToVisitable(v reflect.Value) Visitable {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface().(Visitable)
+}
@@ -0,0 +1,68 @@
+package rel_test
+
+import (
+	. "."
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Walk", func() {
+	It("visits every node of a statement in field order", func() {
+		users := NewTable("users")
+		posts := NewTable("posts")
+		mgr := users.Select(users.Attr("id")).
+			Join(posts).On(posts.Attr("user_id").Eq(users.Attr("id"))).
+			Where(users.Attr("age").Gt(Sql(21)))
+		var attributes []string
+		Walk(mgr.Ast, func(node Visitable) bool {
+			if attr, ok := node.(*AttributeNode); ok {
+				attributes = append(attributes, attr.Name.Raw)
+			}
+			return true
+		})
+		Expect(attributes).To(Equal([]string{"user_id", "id", "id", "age"}))
+	})
+
+	It("skips children when the callback returns false", func() {
+		users := NewTable("users")
+		node := users.Attr("id").Eq(Sql(1)).And(users.Attr("age").Gt(Sql(2)))
+		count := 0
+		Walk(node, func(node Visitable) bool {
+			count++
+			switch node.(type) {
+			case *GroupingNode, *AndNode:
+				return true
+			}
+			return false
+		})
+		Expect(count).To(Equal(4))
+	})
+})
+
+var _ = Describe("Rewrite", func() {
+	It("replaces nodes without modifying the original", func() {
+		users := NewTable("users")
+		mgr := users.Select(users.Attr("id")).Where(users.Attr("id").Eq(Sql(1)))
+		rewritten := Rewrite(mgr, func(node Visitable) Visitable {
+			if eq, ok := node.(*EqualityNode); ok {
+				return eq.Left.(*AttributeNode).NotEq(eq.Right)
+			}
+			return node
+		}).(*SelectManager)
+		Expect(mgr.ToSql()).To(Equal(`SELECT "users"."id" FROM "users" WHERE "users"."id" = 1`))
+		Expect(rewritten.ToSql()).To(Equal(`SELECT "users"."id" FROM "users" WHERE "users"."id" != 1`))
+	})
+
+	It("can add tenant scoping to every select core", func() {
+		users := NewTable("users")
+		mgr := users.Select(users.Attr("id"))
+		rewritten := Rewrite(mgr.Ast, func(node Visitable) Visitable {
+			if core, ok := node.(*SelectCoreNode); ok {
+				core.Wheres = &[]Visitable{users.Attr("tenant_id").Eq(Sql(7))}
+			}
+			return node
+		})
+		sql := RelEngine.Visitor().Accept(rewritten)
+		Expect(sql).To(Equal(`SELECT "users"."id" FROM "users" WHERE "users"."tenant_id" = 7`))
+	})
+})
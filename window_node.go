@@ -1,11 +1,20 @@
 package rel
 
 type WindowNode struct {
-	Orders  *[]Visitable
-	Framing Visitable
+	Partitions *[]Visitable
+	Orders     *[]Visitable
+	Framing    Visitable
 	BaseVisitable
 }
 
+func (node *WindowNode) Partition(visitables ...Visitable) *WindowNode {
+	if node.Partitions == nil {
+		node.Partitions = &[]Visitable{}
+	}
+	*node.Partitions = append(*node.Partitions, visitables...)
+	return node
+}
+
 func (node *WindowNode) Order(v Visitable) *WindowNode {
 	if node.Orders == nil {
 		node.Orders = &[]Visitable{}
@@ -15,12 +24,21 @@ func (node *WindowNode) Order(v Visitable) *WindowNode {
 }
 
 type NamedWindowNode struct {
-	Name    SqlLiteralNode
-	Orders  *[]Visitable
-	Framing Visitable
+	Name       SqlLiteralNode
+	Partitions *[]Visitable
+	Orders     *[]Visitable
+	Framing    Visitable
 	BaseVisitable
 }
 
+func (node *NamedWindowNode) Partition(visitables ...Visitable) *NamedWindowNode {
+	if node.Partitions == nil {
+		node.Partitions = &[]Visitable{}
+	}
+	*node.Partitions = append(*node.Partitions, visitables...)
+	return node
+}
+
 func (node *NamedWindowNode) Order(v Visitable) *NamedWindowNode {
 	if node.Orders == nil {
 		node.Orders = &[]Visitable{}